@@ -7,23 +7,24 @@ import (
 
 	"github.com/DroppedHard/SWIFT-service/service/api"
 	"github.com/DroppedHard/SWIFT-service/service/api/swiftCode"
-	"github.com/DroppedHard/SWIFT-service/service/store"
+	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
 	"github.com/gorilla/mux"
-	"github.com/redis/go-redis/v9"
 )
 
 type APIServer struct {
-	host   string
-	port   string
-	client *redis.Client
+	host             string
+	port             string
+	store            types.BankDataStore
+	idempotencyStore types.IdempotencyStore
 }
 
-func NewAPIServer(host string, port string, client *redis.Client) *APIServer {
+func NewAPIServer(host string, port string, store types.BankDataStore, idempotencyStore types.IdempotencyStore) *APIServer {
 	return &APIServer{
-		host:   host,
-		port:   port,
-		client: client,
+		host:             host,
+		port:             port,
+		store:            store,
+		idempotencyStore: idempotencyStore,
 	}
 }
 
@@ -31,10 +32,9 @@ func (s *APIServer) Run() error {
 	router := mux.NewRouter()
 	subrouter := router.PathPrefix(utils.ApiPrefix).Subrouter()
 
-	bankDataStore := store.NewStore(s.client)
-	swiftCodeHandler := swiftCode.NewSwiftCodeHandler(bankDataStore)
+	swiftCodeHandler := swiftCode.NewSwiftCodeHandler(s.store, s.idempotencyStore)
 	swiftCodeHandler.RegisterRoutes(subrouter)
-	healthCheckHandler := api.NewHealthCheckHandler(bankDataStore)
+	healthCheckHandler := api.NewHealthCheckHandler(s.store)
 	healthCheckHandler.RegisterRoutes(subrouter)
 
 	log.Println("Listening on", fmt.Sprintf("%s:%s", s.host, s.port))
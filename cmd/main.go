@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/DroppedHard/SWIFT-service/cmd/api"
 	"github.com/DroppedHard/SWIFT-service/config"
 	"github.com/DroppedHard/SWIFT-service/db"
+	"github.com/DroppedHard/SWIFT-service/service/store"
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -23,18 +28,59 @@ import (
 // @BasePath /v1
 
 func main() {
-	rdb := db.NewRedisStorage(&redis.Options{
-		Addr:         fmt.Sprintf("%s:%s", config.Envs.DBHost, config.Envs.DBPort),
-		Password:     config.Envs.DBPassword,
-		DB:           config.Envs.DBNum,
-		PoolSize:     config.Envs.DBPoolSize,
-		MinIdleConns: config.Envs.DBMinIdleConns,
-	})
-
-	db.TestClientConection(rdb)
-
-	server := api.NewAPIServer(config.Envs.PublicHost, config.Envs.Port, rdb)
+	bankDataStore, idempotencyStore := newStores()
+	server := api.NewAPIServer(config.Envs.PublicHost, config.Envs.Port, bankDataStore, idempotencyStore)
 	if err := server.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// newStores creates the bank data store of the configured backend. Idempotency keys are kept in Redis
+// together with the bank data, and in the memory of the instance for the other backends.
+func newStores() (types.BankDataStore, types.IdempotencyStore) {
+	idempotencyTTL := time.Duration(config.Envs.IdempotencyTTL) * time.Second
+	if config.Envs.StoreBackend == utils.StoreBackendRedis {
+		rdb := db.NewRedisStorage(&redis.Options{
+			Addr:         fmt.Sprintf("%s:%s", config.Envs.DBHost, config.Envs.DBPort),
+			Password:     config.Envs.DBPassword,
+			DB:           config.Envs.DBNum,
+			PoolSize:     config.Envs.DBPoolSize,
+			MinIdleConns: config.Envs.DBMinIdleConns,
+		})
+		db.TestClientConection(rdb)
+		return store.NewStore(rdb), store.NewRedisIdempotencyStore(rdb, idempotencyTTL)
+	}
+	log.Println("DB: Idempotency keys are kept in memory of this instance")
+	return newBankDataStore(), store.NewMemoryIdempotencyStore(idempotencyTTL)
+}
+
+func newBankDataStore() types.BankDataStore {
+	switch config.Envs.StoreBackend {
+	case utils.StoreBackendMemory:
+		log.Println("DB: Using in-memory store, data will not be persisted")
+		return store.NewMemoryStore()
+	case utils.StoreBackendPostgres:
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		pool, err := db.NewPostgresStorage(ctx, config.Envs.PostgresURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := db.MigratePostgres(ctx, pool); err != nil {
+			log.Fatal(err)
+		}
+		log.Println("DB: Succesfully connected!")
+		return store.NewPostgresStore(pool)
+	case utils.StoreBackendBolt:
+		boltDB, err := db.NewBoltStorage(config.Envs.BoltFilePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Println("DB: Using bolt data file", config.Envs.BoltFilePath)
+		return store.NewBoltStore(boltDB)
+	default:
+		log.Fatalf("unsupported store backend: %s", config.Envs.StoreBackend)
+		return nil
+	}
+}
@@ -7,22 +7,22 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/DroppedHard/SWIFT-service/config"
+	"github.com/DroppedHard/SWIFT-service/db"
+	"github.com/DroppedHard/SWIFT-service/service/store"
+	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-type RedisData struct {
-	Key    string            `json:"key"`
-	Fields map[string]string `json:"fields"`
-}
-
-func parseCSV(file *os.File) ([]RedisData, error) {
-	var data []RedisData
+func parseCSV(file *os.File) ([]types.BankDataDetails, error) {
+	var data []types.BankDataDetails
 	reader := csv.NewReader(file)
-	reader.Comma = ';'
+	reader.Comma = utils.CsvSeparator
 
 	records, err := reader.ReadAll()
 	if err != nil {
@@ -39,32 +39,49 @@ func parseCSV(file *os.File) ([]RedisData, error) {
 			fmt.Println("Error while parsing SWIFT code, skipping the record")
 			continue
 		}
-		address := record[2]
-		isHeadquarter := isHeadquarterParser(swiftCode)
-		bankName := record[1]
-		countryName := strings.ToUpper(utils.GetCountryNameFromCountryCode(countryIso2))
-
-		redisEntry := RedisData{
-			Key: swiftCode,
-			Fields: map[string]string{
-				utils.RedisHashSwiftCode:     swiftCode,
-				utils.RedisHashAddress:       address,
-				utils.RedisHashIsHeadquarter: isHeadquarter,
-				utils.RedisHashCountryISO2:   countryIso2,
-				utils.RedisHashBankName:      bankName,
-				utils.RedisHashCountryName:   countryName,
+
+		data = append(data, types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     swiftCode,
+				Address:       record[2],
+				IsHeadquarter: strings.HasSuffix(swiftCode, utils.BranchSuffix),
+				CountryIso2:   countryIso2,
+				BankName:      record[1],
 			},
-		}
-		data = append(data, redisEntry)
+			CountryName: strings.ToUpper(utils.GetCountryNameFromCountryCode(countryIso2)),
+		})
 	}
 	return data, nil
 }
 
-func isHeadquarterParser(swiftCode string) string {
-	if strings.HasSuffix(swiftCode, utils.BranchSuffix) {
-		return utils.RedisStoreTrue
+func parseNationalBankCodesCSV(file *os.File) ([]types.NationalBankCode, error) {
+	var codes []types.NationalBankCode
+	reader := csv.NewReader(file)
+	reader.Comma = utils.CsvSeparator
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
 	}
-	return utils.RedisStoreFalse
+
+	for _, record := range records[1:] {
+		if len(record) < 3 {
+			continue
+		}
+		code := types.NationalBankCode{
+			CountryIso2:      strings.ToUpper(strings.TrimSpace(record[0])),
+			NationalBankCode: strings.TrimSpace(record[1]),
+			SwiftCode:        utils.NormalizeSwiftCode(record[2]),
+		}
+		if code.NationalBankCode == "" ||
+			utils.Validate.Var(code.CountryIso2, utils.ValidatorCountryIso2) != nil ||
+			utils.Validate.Var(code.SwiftCode, utils.ValidatorSwiftCode) != nil {
+			fmt.Println("Error while parsing national bank code, skipping the record")
+			continue
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
 }
 
 func connectToRedis() *redis.Client {
@@ -99,23 +116,127 @@ func connectToRedis() *redis.Client {
 	return nil
 }
 
-func startMigration(data []RedisData, rdb *redis.Client) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func connectToPostgres() *pgxpool.Pool {
+	retryCount := 10
+	delay := 2 * time.Second
 
+	for i := 0; i < retryCount; i++ {
+		fmt.Printf("Postgres connection attempt %d...\n", i)
+
+		ctx, cancel := context.WithTimeout(context.Background(), delay)
+		pool, err := db.NewPostgresStorage(ctx, config.Envs.PostgresURL)
+		cancel()
+		if err == nil {
+			fmt.Println("Connected to Postgres!")
+			return pool
+		}
+
+		fmt.Printf("Postgres connection failed: %v. Retrying in %v...\n", err, delay)
+		time.Sleep(delay)
+	}
+
+	fmt.Println("Failed to connect to Postgres after multiple attempts. Exiting.")
+	os.Exit(1)
+	return nil
+}
+
+func connectToStore() types.BankDataStore {
+	switch config.Envs.StoreBackend {
+	case utils.StoreBackendRedis:
+		return store.NewStore(connectToRedis())
+	case utils.StoreBackendPostgres:
+		pool := connectToPostgres()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := db.MigratePostgres(ctx, pool); err != nil {
+			fmt.Printf("Failed to migrate Postgres schema: %v\n", err)
+			os.Exit(1)
+		}
+		return store.NewPostgresStore(pool)
+	case utils.StoreBackendBolt:
+		boltDB, err := db.NewBoltStorage(config.Envs.BoltFilePath)
+		if err != nil {
+			fmt.Printf("Failed to open bolt data file: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Opened bolt data file", config.Envs.BoltFilePath)
+		return store.NewBoltStore(boltDB)
+	default:
+		fmt.Printf("Store backend %s is not supported by the migration app. Exiting.\n", config.Envs.StoreBackend)
+		os.Exit(1)
+		return nil
+	}
+}
+
+// startMigration saves the data in batches of DBBatchSize, handled by at most DBPoolSize workers, so the store's
+// connection pool is never flooded. Every batch gets its own timeout, so big files do not run out of time.
+func startMigration(data []types.BankDataDetails, bankDataStore types.BankDataStore) {
+	batches := make(chan []types.BankDataDetails)
+	var failed atomic.Int64
 	var wg sync.WaitGroup
-	for _, entry := range data {
+	for i := 0; i < max(config.Envs.DBPoolSize, 1); i++ {
 		wg.Add(1)
-		go func(entry RedisData) {
+		go func() {
 			defer wg.Done()
-
-			if _, err := rdb.HSet(ctx, entry.Key, entry.Fields).Result(); err != nil {
-				fmt.Printf("Failed to populate key %s: %v\n", entry.Key, err)
-				return
+			for batch := range batches {
+				failed.Add(int64(migrateBatch(batch, bankDataStore)))
 			}
-			fmt.Printf("Successfully populated key: %s\n", entry.Key)
-		}(entry)
+		}()
+	}
+	batchSize := max(config.Envs.DBBatchSize, 1)
+	for start := 0; start < len(data); start += batchSize {
+		batches <- data[start:min(start+batchSize, len(data))]
 	}
+	close(batches)
 	wg.Wait()
-	fmt.Println("Migration completed successfully.")
+
+	if failed.Load() > 0 {
+		fmt.Printf("Migration completed with %d of %d records failed.\n", failed.Load(), len(data))
+		return
+	}
+	fmt.Println("Migration completed successfully. The search index was built along with the data.")
+}
+
+// migrateBatch saves the batch entry by entry and returns the number of entries that failed.
+func migrateBatch(batch []types.BankDataDetails, bankDataStore types.BankDataStore) int {
+	ctx, cancel := context.WithTimeout(context.Background(), utils.MigrationBatchTimeoutSecs*time.Second)
+	defer cancel()
+
+	failed := 0
+	for _, entry := range batch {
+		if err := bankDataStore.SaveBankData(ctx, entry); err != nil {
+			fmt.Printf("Failed to populate key %s: %v\n", entry.SwiftCode, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Successfully populated key: %s\n", entry.SwiftCode)
+	}
+	return failed
+}
+
+func migrateNationalBankCodes(codes []types.NationalBankCode, bankDataStore types.BankDataStore) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, code := range codes {
+		if err := bankDataStore.SaveNationalBankCode(ctx, code); err != nil {
+			fmt.Printf("Failed to populate national bank code %s of %s: %v\n", code.NationalBankCode, code.CountryIso2, err)
+			continue
+		}
+		fmt.Printf("Successfully populated national bank code %s of %s: %s\n", code.NationalBankCode, code.CountryIso2, code.SwiftCode)
+	}
+	fmt.Println("National bank codes migration completed.")
+}
+
+func rebuildIndexes(rebuilder types.IndexRebuilder) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	indexed, err := rebuilder.RebuildIndexes(ctx)
+	if err != nil {
+		fmt.Printf("Failed to rebuild indexes: %v\n", err)
+		return
+	}
+	fmt.Printf("Indexes rebuilt successfully for %d SWIFT codes.\n", indexed)
 }
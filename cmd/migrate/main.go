@@ -7,14 +7,46 @@ import (
 	"strings"
 
 	"github.com/DroppedHard/SWIFT-service/config"
+	"github.com/DroppedHard/SWIFT-service/types"
 )
 
 func main() {
-	var filePath string
+	var (
+		filePath          string
+		bankCodesFilePath string
+		shouldRebuild     bool
+	)
 	flag.StringVar(&filePath, "source", config.Envs.MigrationFilePath, "Path to the JSON file containing migration data")
+	flag.StringVar(&bankCodesFilePath, "national-bank-codes", "", "Path to the CSV file mapping national bank codes to SWIFT codes, imported instead of bank data")
+	flag.BoolVar(&shouldRebuild, "rebuild-indexes", false, "Rebuild lookup and search indexes for already stored data instead of importing a file")
 	flag.Parse()
 
-	rdb := connectToRedis()
+	if shouldRebuild {
+		rebuilder, ok := connectToStore().(types.IndexRebuilder)
+		if !ok {
+			fmt.Printf("Index rebuild is not needed for the %s store backend.\n", config.Envs.StoreBackend)
+			return
+		}
+		rebuildIndexes(rebuilder)
+		return
+	}
+
+	if bankCodesFilePath != "" {
+		file, err := os.Open(bankCodesFilePath)
+		if err != nil {
+			fmt.Printf("Failed to open the file: %v\n", err)
+			return
+		}
+		defer file.Close()
+
+		codes, err := parseNationalBankCodesCSV(file)
+		if err != nil {
+			fmt.Printf("Failed to decode file: %v\n", err)
+			return
+		}
+		migrateNationalBankCodes(codes, connectToStore())
+		return
+	}
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -23,7 +55,7 @@ func main() {
 	}
 	defer file.Close()
 
-	var data []RedisData
+	var data []types.BankDataDetails
 	switch {
 	case strings.HasSuffix(filePath, ".csv"):
 		data, err = parseCSV(file)
@@ -37,5 +69,5 @@ func main() {
 		return
 	}
 
-	startMigration(data, rdb)
+	startMigration(data, connectToStore())
 }
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DroppedHard/SWIFT-service/utils"
+	"go.etcd.io/bbolt"
+)
+
+// NewBoltStorage opens (or creates) the bbolt data file and makes sure all buckets used by the store exist.
+func NewBoltStorage(path string) (*bbolt.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	boltDB, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt file %s: %w", path, err)
+	}
+
+	err = boltDB.Update(func(tx *bbolt.Tx) error {
+		for _, bucket := range utils.BoltBuckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(bucket)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		boltDB.Close()
+		return nil, fmt.Errorf("failed to create bolt buckets: %w", err)
+	}
+	return boltDB, nil
+}
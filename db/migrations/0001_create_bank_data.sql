@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS bank_data (
+    swift_code     TEXT PRIMARY KEY,
+    bic8           TEXT GENERATED ALWAYS AS (substr(swift_code, 1, 8)) STORED,
+    country_code   TEXT GENERATED ALWAYS AS (substr(swift_code, 5, 2)) STORED,
+    bank_name      TEXT NOT NULL,
+    address        TEXT NOT NULL,
+    country_iso2   TEXT NOT NULL,
+    country_name   TEXT NOT NULL,
+    is_headquarter BOOLEAN NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS bank_data_country_code_idx ON bank_data (country_code);
+CREATE INDEX IF NOT EXISTS bank_data_bic8_idx ON bank_data (bic8);
@@ -0,0 +1,3 @@
+ALTER TABLE bank_data ADD COLUMN IF NOT EXISTS bank_code TEXT GENERATED ALWAYS AS (substr(swift_code, 1, 4)) STORED;
+
+CREATE INDEX IF NOT EXISTS bank_data_bank_code_idx ON bank_data (bank_code);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS bank_search_terms (
+    term       TEXT NOT NULL,
+    swift_code TEXT NOT NULL REFERENCES bank_data (swift_code) ON DELETE CASCADE,
+    weight     INTEGER NOT NULL,
+    PRIMARY KEY (term, swift_code)
+);
+
+CREATE INDEX IF NOT EXISTS bank_search_terms_swift_code_idx ON bank_search_terms (swift_code);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS bank_autocomplete_terms (
+    term       TEXT NOT NULL,
+    swift_code TEXT NOT NULL REFERENCES bank_data (swift_code) ON DELETE CASCADE,
+    PRIMARY KEY (swift_code, term)
+);
+
+CREATE INDEX IF NOT EXISTS bank_autocomplete_terms_term_idx ON bank_autocomplete_terms (term text_pattern_ops);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS national_bank_codes (
+    country_iso2       TEXT NOT NULL,
+    national_bank_code TEXT NOT NULL,
+    swift_code         TEXT NOT NULL,
+    PRIMARY KEY (country_iso2, national_bank_code)
+);
@@ -0,0 +1 @@
+ALTER TABLE bank_data ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
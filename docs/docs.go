@@ -23,88 +23,142 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
+        "/autocomplete": {
             "get": {
-                "description": "endpoint to verify whether system is healthy, or not",
+                "description": "Use it to suggest banks whose SWIFT code or bank name starts with the prefix. Matching ignores case, accents and punctuation, and headquarters are listed before branches.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "status"
+                    "bank"
+                ],
+                "summary": "Bank typeahead",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Beginning of the SWIFT code or bank name",
+                        "name": "prefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (1-50, default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "System health check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.AutocompleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
                         }
                     }
                 }
             }
         },
-        "/swift-codes/": {
-            "post": {
-                "description": "Use it to add new bank data - verify data correctiness",
-                "consumes": [
-                    "application/json"
-                ],
+        "/banks/{bankCode}": {
+            "get": {
+                "description": "Use it to fetch every headquarter and branch sharing the bank code - the first 4 characters of the SWIFT code - across all countries, grouped by country.\nCountries are ordered by ISO2 code and banks by SWIFT code.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "bank"
                 ],
-                "summary": "Add bank data to the system",
+                "summary": "Bank code to banking group data",
                 "parameters": [
                     {
-                        "description": "Bank data",
-                        "name": "bankData",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.BankDataDetails"
-                        }
+                        "type": "string",
+                        "description": "Bank code - first 4 characters of the SWIFT code",
+                        "name": "bankCode",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.BankGroupResponse"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "$ref": "#/definitions/types.BankGroupResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     }
                 }
             }
         },
-        "/swift-codes/country/{countryISO2}": {
+        "/countries": {
             "get": {
-                "description": "Use it to fetch banks data by country ISO2 code",
+                "description": "Use it to list every country with stored SWIFT codes, with the number of all codes, headquarters and branches - ordered by country ISO2 code",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "bank"
                 ],
-                "summary": "Country code to bank data",
+                "summary": "Countries catalogue",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountriesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/countries/{countryISO2}": {
+            "get": {
+                "description": "Use it to fetch the number of all SWIFT codes, headquarters and branches stored for the country",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Country coverage",
                 "parameters": [
                     {
                         "type": "string",
@@ -118,23 +172,100 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.CountrySwiftCodesResponse"
+                            "$ref": "#/definitions/types.CountryStats"
                         }
                     },
-                    "206": {
-                        "description": "Partial Content",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/types.CountrySwiftCodesResponse"
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/export": {
+            "get": {
+                "description": "Use it to download a full or incremental copy of the bank data - records are streamed one per line as NDJSON (with their modification time, if known) or as CSV in the migration app layout. Records are not ordered. Records stored before modification times were tracked are always included. If the store fails mid-stream the connection is aborted, so a complete response always ends cleanly.",
+                "produces": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Export all bank data",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Export format (default ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country ISO2 code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time - only records modified at or after it are exported",
+                        "name": "modifiedSince",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.BankDataRecord"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "endpoint to verify whether system is healthy, or not",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "System health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/types.ReturnMessage"
                         }
@@ -142,21 +273,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/swift-codes/{swiftCode}": {
+        "/iban/{iban}/bic": {
             "get": {
-                "description": "Use it to fetch bank data by SWIFT code - if it is a HQ it branches will be retrieved too",
+                "description": "Use it to find the BIC of the bank holding the account - the IBAN is validated and its national bank code is resolved to a stored SWIFT code.\nThe bank code together with the branch code is tried before the bank code alone. For a branch, its headquarter is included too.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "bank"
                 ],
-                "summary": "Swift code to bank data",
+                "summary": "IBAN to bank data",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bank swift code",
-                        "name": "swiftCode",
+                        "description": "IBAN, spaces are ignored",
+                        "name": "iban",
                         "in": "path",
                         "required": true
                     }
@@ -165,110 +296,932 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.BankHeadquatersResponse"
-                        }
-                    },
-                    "206": {
-                        "description": "Partial Content",
-                        "schema": {
-                            "$ref": "#/definitions/types.BankHeadquatersResponse"
+                            "$ref": "#/definitions/types.IbanBicResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Use it to delete bank data by SWIFT code",
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Use it to search banks by words of their name and address. Matching ignores case and accents, and results are ranked by relevance - name matches weigh more than address matches.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "bank"
                 ],
-                "summary": "Delete bank data from the system",
+                "summary": "Full-text bank search",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bank swift code",
-                        "name": "swiftCode",
-                        "in": "path",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.SearchResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes": {
+            "get": {
+                "description": "Use it to fetch banks data matching all of the given filters - country or bankCode is required. Results are paginated like the country listing.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Find bank data by filters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "country ISO2 code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bank code - first 4 characters of the SWIFT code",
+                        "name": "bankCode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Headquarters only (true) or branches only (false)",
+                        "name": "isHeadquarter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive part of the bank name",
+                        "name": "bankName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to fetch",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "swiftCode",
+                            "bankName"
+                        ],
+                        "type": "string",
+                        "description": "Sort field (default swiftCode)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response media type - application/json (default), application/xml, application/x-ndjson or text/csv",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.SwiftCodesResponse"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "$ref": "#/definitions/types.SwiftCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/types.ReturnMessage"
+                            "$ref": "#/definitions/types.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "types.BankDataCore": {
-            "type": "object",
-            "required": [
-                "address",
-                "bankName",
-                "countryISO2",
-                "swiftCode"
-            ],
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "bankName": {
-                    "type": "string"
-                },
-                "countryISO2": {
-                    "type": "string"
-                },
-                "isHeadquarter": {
-                    "type": "boolean"
-                },
+        },
+        "/swift-codes/": {
+            "post": {
+                "description": "Use it to add new bank data - verify data correctiness",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Add bank data to the system",
+                "parameters": [
+                    {
+                        "description": "Bank data",
+                        "name": "bankData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.BankDataDetails"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request - a retry with the same key gets the stored response replayed",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.ReturnMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/batch": {
+            "post": {
+                "description": "Use it to add many bank data entries at once - every entry is verified separately and gets its own status (created, conflict, invalid).\nWith atomic=true either all entries are added in a single transaction or none of them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Add multiple bank data entries to the system",
+                "parameters": [
+                    {
+                        "description": "Bank data entries",
+                        "name": "bankData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.BankDataDetails"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add all entries or none of them",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request - a retry with the same key gets the stored response replayed",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.BatchCreateResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/types.BatchCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.BatchCreateResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.BatchCreateResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/country/{countryISO2}": {
+            "get": {
+                "description": "Use it to fetch banks data by country ISO2 code. Results are paginated - pass nextCursor from the response as cursor to get the next page.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Country code to bank data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "country ISO2 code",
+                        "name": "countryISO2",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to fetch",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "swiftCode",
+                            "bankName"
+                        ],
+                        "type": "string",
+                        "description": "Sort field (default swiftCode)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response media type - application/json (default), application/xml, application/x-ndjson or text/csv",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response - 304 is returned if it is still current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountrySwiftCodesResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Strong entity tag of the response"
+                            }
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountrySwiftCodesResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Cached response is still current",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Strong entity tag of the response"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/lookup": {
+            "post": {
+                "description": "Use it to fetch bank data of up to 1000 SWIFT codes in one request. Every SWIFT code is reported once - as found, not found or invalid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Look up many SWIFT codes at once",
+                "parameters": [
+                    {
+                        "description": "SWIFT codes to look up",
+                        "name": "lookup",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.LookupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.LookupResponse"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "$ref": "#/definitions/types.LookupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/{swiftCode}": {
+            "get": {
+                "description": "Use it to fetch bank data by SWIFT code - if it is a HQ it branches will be retrieved too\nA branch comes with a summary of its headquarters and the number of its sibling branches. Branches without a stored headquarters are marked as orphan (see types.BankBranchResponse).",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Swift code to bank data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank swift code, BIC8 or BIC11 in any case",
+                        "name": "swiftCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response media type - application/json (default), application/xml, application/x-ndjson or text/csv",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response - 304 is returned if it is still current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.BankHeadquatersResponse"
+                        },
+                        "headers": {
+                            "Content-Location": {
+                                "type": "string",
+                                "description": "Canonical URL of the bank data"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Strong entity tag of the response"
+                            }
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "$ref": "#/definitions/types.BankHeadquatersResponse"
+                        },
+                        "headers": {
+                            "Content-Location": {
+                                "type": "string",
+                                "description": "Canonical URL of the bank data"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Cached response is still current",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Strong entity tag of the response"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Use it to replace all data of an existing SWIFT code - the SWIFT code itself cannot be changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Replace bank data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank swift code, BIC8 or BIC11 in any case",
+                        "name": "swiftCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bank data",
+                        "name": "bankData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.BankDataDetails"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request - a retry with the same key gets the stored response replayed",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the bank data read or its version in double quotes, comma separated - the write fails with 412 if the bank data is at another version",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ReturnMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Use it to delete bank data by SWIFT code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Delete bank data from the system",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank swift code, BIC8 or BIC11 in any case",
+                        "name": "swiftCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request - a retry with the same key gets the stored response replayed",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the bank data read or its version in double quotes, comma separated - the write fails with 412 if the bank data is at another version",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ReturnMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Use it to update chosen fields of an existing SWIFT code with a JSON Merge Patch - the SWIFT code itself cannot be changed",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Partially update bank data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank swift code, BIC8 or BIC11 in any case",
+                        "name": "swiftCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON Merge Patch of the bank data",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request - a retry with the same key gets the stored response replayed",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the bank data read or its version in double quotes, comma separated - the write fails with 412 if the bank data is at another version",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ReturnMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/{swiftCode}/validate": {
+            "get": {
+                "description": "Use it to check whether the SWIFT code is well-formed and decode it into bank, country, location and branch codes - the SWIFT code does not have to be stored in the system.\nA location code ending with 0 marks a test code and ending with 1 a passive participant. BIC8 codes are normalized to the BIC11 of the primary office.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Decode SWIFT code structure",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank swift code",
+                        "name": "swiftCode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.SwiftCodeStructure"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "types.AutocompleteResponse": {
+            "type": "object",
+            "properties": {
+                "prefix": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BankDataCore"
+                    }
+                }
+            }
+        },
+        "types.BankDataCore": {
+            "type": "object",
+            "required": [
+                "address",
+                "bankName",
+                "countryISO2",
+                "swiftCode"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bankName": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "swiftCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.BankDataDetails": {
+            "type": "object",
+            "required": [
+                "address",
+                "bankName",
+                "countryISO2",
+                "countryName",
+                "swiftCode"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bankName": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
                 "swiftCode": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "types.BankDataDetails": {
+        "types.BankDataRecord": {
             "type": "object",
             "required": [
                 "address",
@@ -295,6 +1248,52 @@ const docTemplate = `{
                 },
                 "swiftCode": {
                     "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.BankGroupCountry": {
+            "type": "object",
+            "properties": {
+                "branches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BankDataCore"
+                    }
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "headquarters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BankDataCore"
+                    }
+                }
+            }
+        },
+        "types.BankGroupResponse": {
+            "type": "object",
+            "properties": {
+                "bankCode": {
+                    "type": "string"
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BankGroupCountry"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
@@ -331,6 +1330,77 @@ const docTemplate = `{
                 },
                 "swiftCode": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.BatchCreateResponse": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BatchItemResult"
+                    }
+                }
+            }
+        },
+        "types.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "swiftCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CountriesResponse": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CountryStats"
+                    }
+                }
+            }
+        },
+        "types.CountryStats": {
+            "type": "object",
+            "properties": {
+                "branches": {
+                    "type": "integer"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "headquarters": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
@@ -343,11 +1413,117 @@ const docTemplate = `{
                 "countryName": {
                     "type": "string"
                 },
+                "nextCursor": {
+                    "type": "string"
+                },
                 "swiftCodes": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/types.BankDataCore"
                     }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.IbanBicResponse": {
+            "type": "object",
+            "properties": {
+                "bank": {
+                    "$ref": "#/definitions/types.BankDataDetails"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "headquarter": {
+                    "$ref": "#/definitions/types.BankDataDetails"
+                },
+                "iban": {
+                    "type": "string"
+                },
+                "nationalBankCode": {
+                    "type": "string"
+                },
+                "swiftCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.LookupRequest": {
+            "type": "object",
+            "properties": {
+                "swiftCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.LookupResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "found": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BankDataDetails"
+                    }
+                },
+                "invalid": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "notFound": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.FieldError"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
@@ -358,6 +1534,110 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "types.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.SearchResult"
+                    }
+                }
+            }
+        },
+        "types.SearchResult": {
+            "type": "object",
+            "required": [
+                "address",
+                "bankName",
+                "countryISO2",
+                "swiftCode"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bankName": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "swiftCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.SwiftCodeStructure": {
+            "type": "object",
+            "properties": {
+                "bankCode": {
+                    "type": "string"
+                },
+                "branchCode": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "isPassive": {
+                    "type": "boolean"
+                },
+                "isTestCode": {
+                    "type": "boolean"
+                },
+                "locationCode": {
+                    "type": "string"
+                },
+                "normalized": {
+                    "type": "string"
+                },
+                "swiftCode": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.SwiftCodesResponse": {
+            "type": "object",
+            "properties": {
+                "nextCursor": {
+                    "type": "string"
+                },
+                "swiftCodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BankDataCore"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+)
+
+// ExportWriter streams exported bank data as NDJSON or as CSV in the layout imported by the migration app.
+// The response headers are sent with the first record, so a store failure before it can still be reported as a problem,
+// and the written records are flushed to the client every utils.ExportBatchSize records.
+type ExportWriter struct {
+	w       http.ResponseWriter
+	format  string
+	encoder *json.Encoder
+	csv     *csv.Writer
+	started bool
+	count   int
+}
+
+func NewExportWriter(w http.ResponseWriter, format string) *ExportWriter {
+	return &ExportWriter{w: w, format: format}
+}
+
+// Started reports whether the response status was already sent.
+func (e *ExportWriter) Started() bool {
+	return e.started
+}
+
+func (e *ExportWriter) Write(record types.BankDataRecord) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	var err error
+	if e.csv != nil {
+		err = e.csv.Write(csvBankDataRow(record.BankDataCore))
+	} else {
+		err = e.encoder.Encode(record)
+	}
+	if err != nil {
+		return err
+	}
+	e.count++
+	if e.count%utils.ExportBatchSize == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// Close sends the response of an empty export and flushes the remaining records.
+func (e *ExportWriter) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.flush()
+}
+
+func (e *ExportWriter) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	if e.format == utils.ExportFormatCsv {
+		e.w.Header().Set(utils.HeaderContentType, utils.ContentTypeCsv)
+		e.w.WriteHeader(http.StatusOK)
+		e.csv = csv.NewWriter(e.w)
+		e.csv.Comma = utils.CsvSeparator
+		return e.csv.Write(utils.CsvBankDataHeader)
+	}
+	e.w.Header().Set(utils.HeaderContentType, utils.ContentTypeNdjson)
+	e.w.WriteHeader(http.StatusOK)
+	e.encoder = json.NewEncoder(e.w)
+	return nil
+}
+
+func (e *ExportWriter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
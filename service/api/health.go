@@ -7,6 +7,7 @@ import (
 	_ "github.com/DroppedHard/SWIFT-service/docs"
 
 	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
@@ -36,7 +37,7 @@ func (h *HealthCheckHandler) getHealthCheck(w http.ResponseWriter, r *http.Reque
 
 	err := h.store.Ping(ctx)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, fmt.Errorf("redis database unavailable: %v", err))
+		WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("database unavailable: %v", err))
 		return
 	}
 
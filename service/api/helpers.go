@@ -16,8 +16,44 @@ func ParseJson(r *http.Request, payload any) error {
 	return json.NewDecoder(r.Body).Decode(payload)
 }
 
+// MergePatchJson applies a JSON Merge Patch (RFC 7396) to the original document.
+func MergePatchJson(original []byte, patch []byte) ([]byte, error) {
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	var originalValue interface{}
+	if err := json.Unmarshal(original, &originalValue); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	return json.Marshal(mergePatch(originalValue, patchValue))
+}
+
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
 func WriteJson(w http.ResponseWriter, status int, v any) error {
-	w.Header().Add("Content-Type", "application/json")
+	return writeJsonAs(w, utils.ContentTypeJson, status, v)
+}
+
+func writeJsonAs(w http.ResponseWriter, contentType string, status int, v any) error {
+	w.Header().Add(utils.HeaderContentType, contentType)
 	w.WriteHeader(status)
 
 	return json.NewEncoder(w).Encode(v)
@@ -27,6 +63,7 @@ func WriteMessage(w http.ResponseWriter, status int, mess string) {
 	WriteJson(w, status, map[string]string{utils.ResponseMessageField: mess})
 }
 
-func WriteError(w http.ResponseWriter, status int, err error) {
-	WriteJson(w, status, map[string]string{utils.ResponseMessageField: err.Error()})
+// WriteError writes the error as an application/problem+json body with the given problem code.
+func WriteError(w http.ResponseWriter, status int, code string, err error) {
+	writeJsonAs(w, utils.ContentTypeProblemJson, status, NewProblem(status, code, err))
 }
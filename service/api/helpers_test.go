@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/DroppedHard/SWIFT-service/service/api"
+	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -118,32 +119,144 @@ func TestWriteMessage(t *testing.T) {
 }
 
 func TestWriteError(t *testing.T) {
-	tests := []HelpersTestCase{
+	tests := []struct {
+		Description     string
+		StatusCode      int
+		Code            string
+		Err             error
+		ExpectedProblem types.Problem
+	}{
 		{
-			Description:  "Write error with OK status",
-			StatusCode:   http.StatusBadRequest,
-			Message:      "Invalid request",
-			ExpectedBody: map[string]string{utils.ResponseMessageField: "Invalid request"},
+			Description: "Write error with Not Found status",
+			StatusCode:  http.StatusNotFound,
+			Code:        utils.ProblemNotFound,
+			Err:         fmt.Errorf("Missing resource"),
+			ExpectedProblem: types.Problem{
+				Type:   utils.ProblemTypePrefix + utils.ProblemNotFound,
+				Title:  "Resource not found",
+				Status: http.StatusNotFound,
+				Detail: "Missing resource",
+				Code:   utils.ProblemNotFound,
+			},
+		},
+		{
+			Description: "Write error with Internal Server Error status",
+			StatusCode:  http.StatusInternalServerError,
+			Code:        utils.ProblemStoreUnavailable,
+			Err:         fmt.Errorf("Server error"),
+			ExpectedProblem: types.Problem{
+				Type:   utils.ProblemTypePrefix + utils.ProblemStoreUnavailable,
+				Title:  "Bank data store unavailable",
+				Status: http.StatusInternalServerError,
+				Detail: "Server error",
+				Code:   utils.ProblemStoreUnavailable,
+			},
 		},
 		{
-			Description:  "Write error with Internal Server Error status",
-			StatusCode:   http.StatusInternalServerError,
-			Message:      "Server error",
-			ExpectedBody: map[string]string{utils.ResponseMessageField: "Server error"},
+			Description: "Write wrapped validation error",
+			StatusCode:  http.StatusBadRequest,
+			Code:        utils.ProblemValidationFailed,
+			Err: fmt.Errorf("validation error: %w", utils.ValidationError{Errors: map[string]string{
+				"swiftCode": "validation failed on 'required' tag",
+				"address":   "validation failed on 'required' tag",
+			}}),
+			ExpectedProblem: types.Problem{
+				Type:   utils.ProblemTypePrefix + utils.ProblemValidationFailed,
+				Title:  "Validation failed",
+				Status: http.StatusBadRequest,
+				Detail: "2 fields failed validation",
+				Code:   utils.ProblemValidationFailed,
+				Errors: []types.FieldError{
+					{Field: "address", Message: "validation failed on 'required' tag"},
+					{Field: "swiftCode", Message: "validation failed on 'required' tag"},
+				},
+			},
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.Description, func(t *testing.T) {
 			rr := httptest.NewRecorder()
-			api.WriteError(rr, test.StatusCode, fmt.Errorf("%s", test.Message))
+			api.WriteError(rr, test.StatusCode, test.Code, test.Err)
 
 			assert.Equal(t, test.StatusCode, rr.Code)
+			assert.Equal(t, utils.ContentTypeProblemJson, rr.Header().Get(utils.HeaderContentType))
 
-			var response map[string]string
+			var response types.Problem
 			err := json.Unmarshal(rr.Body.Bytes(), &response)
 			assert.NoError(t, err)
-			assert.Equal(t, test.ExpectedBody, response)
+			assert.Equal(t, test.ExpectedProblem, response)
+		})
+	}
+}
+
+func TestProblemCode(t *testing.T) {
+	err := fmt.Errorf("validation error: %w", utils.ProblemError{Code: utils.ProblemCountryMismatch, Err: fmt.Errorf("mismatch")})
+	assert.Equal(t, utils.ProblemCountryMismatch, api.ProblemCode(err, utils.ProblemValidationFailed))
+	assert.Equal(t, utils.ProblemValidationFailed, api.ProblemCode(fmt.Errorf("plain"), utils.ProblemValidationFailed))
+}
+
+func TestProblemTitlesCatalogue(t *testing.T) {
+	for _, code := range []string{
+		utils.ProblemInvalidSwiftCode, utils.ProblemInvalidCountry, utils.ProblemInvalidBankCode, utils.ProblemInvalidIban,
+		utils.ProblemInvalidPathParam, utils.ProblemInvalidQuery, utils.ProblemInvalidBody, utils.ProblemValidationFailed,
+		utils.ProblemCountryMismatch, utils.ProblemHqMismatch, utils.ProblemSwiftCodeChanged, utils.ProblemDuplicate,
+		utils.ProblemNotFound, utils.ProblemNotAcceptable, utils.ProblemStoreUnavailable, utils.ProblemInternal,
+		utils.ProblemInvalidIdemKey, utils.ProblemIdemKeyReused, utils.ProblemIdemKeyInUse,
+	} {
+		assert.NotEmpty(t, api.ProblemTitles[code], code)
+	}
+}
+
+func TestMergePatchJson(t *testing.T) {
+	tests := []struct {
+		Description string
+		Original    string
+		Patch       string
+		Expected    string
+		ExpectedErr string
+	}{
+		{
+			Description: "Replace a field",
+			Original:    `{"address":"Old Street 1","bankName":"Bank"}`,
+			Patch:       `{"address":"New Street 1"}`,
+			Expected:    `{"address":"New Street 1","bankName":"Bank"}`,
+		},
+		{
+			Description: "Remove a field with null",
+			Original:    `{"address":"Old Street 1","bankName":"Bank"}`,
+			Patch:       `{"address":null}`,
+			Expected:    `{"bankName":"Bank"}`,
+		},
+		{
+			Description: "Merge nested objects",
+			Original:    `{"a":{"b":1,"c":2}}`,
+			Patch:       `{"a":{"c":null,"d":3}}`,
+			Expected:    `{"a":{"b":1,"d":3}}`,
+		},
+		{
+			Description: "Non-object patch replaces the document",
+			Original:    `{"a":1}`,
+			Patch:       `["b"]`,
+			Expected:    `["b"]`,
+		},
+		{
+			Description: "Invalid patch",
+			Original:    `{"a":1}`,
+			Patch:       `{"a":`,
+			ExpectedErr: "invalid merge patch",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			result, err := api.MergePatchJson([]byte(test.Original), []byte(test.Patch))
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.Expected, string(result))
 		})
 	}
 }
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+)
+
+// MediaType is the response format negotiated with the Accept header.
+type MediaType string
+
+// SupportedMediaTypes lists the formats of the read endpoints, the first one being the default.
+var SupportedMediaTypes = []MediaType{utils.ContentTypeJson, utils.ContentTypeXml, utils.ContentTypeNdjson, utils.ContentTypeCsv}
+
+// NegotiateMediaType picks the supported media type with the highest quality in the Accept header,
+// preferring the earlier ones on a tie. An empty header accepts JSON.
+func NegotiateMediaType(accept string) (MediaType, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return SupportedMediaTypes[0], true
+	}
+	var best MediaType
+	bestQuality := 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(mediaRange, ";")
+		quality := acceptQuality(params)
+		if quality <= bestQuality {
+			continue
+		}
+		if mediaType, ok := matchMediaRange(strings.ToLower(strings.TrimSpace(mediaRange))); ok {
+			best, bestQuality = mediaType, quality
+		}
+	}
+	return best, best != ""
+}
+
+func acceptQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if name == "q" {
+			quality, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0
+			}
+			return quality
+		}
+	}
+	return 1
+}
+
+func matchMediaRange(mediaRange string) (MediaType, bool) {
+	for _, mediaType := range SupportedMediaTypes {
+		mainType, _, _ := strings.Cut(string(mediaType), "/")
+		if mediaRange == string(mediaType) || mediaRange == "*/*" || mediaRange == mainType+"/*" {
+			return mediaType, true
+		}
+	}
+	return "", false
+}
+
+// MediaTypeFromContext returns the media type negotiated by the middleware, or JSON if there was none.
+func MediaTypeFromContext(ctx context.Context) MediaType {
+	mediaType, ok := ctx.Value(reflect.TypeOf(MediaType(""))).(MediaType)
+	if !ok {
+		return SupportedMediaTypes[0]
+	}
+	return mediaType
+}
+
+// WriteNegotiated writes the response in the negotiated media type. JSON and XML carry the whole response,
+// while CSV - in the layout imported by the migration app - and NDJSON carry the bank rows only.
+func WriteNegotiated(w http.ResponseWriter, r *http.Request, status int, v any, rows []types.BankDataCore) error {
+	w.Header().Add(utils.HeaderVary, utils.HeaderAccept)
+	mediaType := MediaTypeFromContext(r.Context())
+	w.Header().Add(utils.HeaderContentType, string(mediaType))
+	w.WriteHeader(status)
+
+	return encodeNegotiated(w, mediaType, v, rows)
+}
+
+// WriteNegotiatedWithETag writes a 200 response like WriteNegotiated, tagged with a strong ETag - the SHA-256 of the encoded body,
+// which differs between the media types. If the request's If-None-Match matches it, 304 Not Modified is written instead.
+func WriteNegotiatedWithETag(w http.ResponseWriter, r *http.Request, v any, rows []types.BankDataCore) error {
+	return writeNegotiatedWithETag(w, r, "", v, rows)
+}
+
+// WriteVersionedNegotiated writes a single bank data record like WriteNegotiatedWithETag, with the record version leading
+// the ETag, e.g. "3-9f86d0...". The ETag still changes with any change of the body, and can be sent back in If-Match.
+func WriteVersionedNegotiated(w http.ResponseWriter, r *http.Request, version int64, v any, rows []types.BankDataCore) error {
+	return writeNegotiatedWithETag(w, r, strconv.FormatInt(version, 10)+utils.ETagVersionSeparator, v, rows)
+}
+
+func writeNegotiatedWithETag(w http.ResponseWriter, r *http.Request, prefix string, v any, rows []types.BankDataCore) error {
+	w.Header().Add(utils.HeaderVary, utils.HeaderAccept)
+	mediaType := MediaTypeFromContext(r.Context())
+	var body bytes.Buffer
+	if err := encodeNegotiated(&body, mediaType, v, rows); err != nil {
+		return err
+	}
+
+	etag := fmt.Sprintf(`"%s%x"`, prefix, sha256.Sum256(body.Bytes()))
+	w.Header().Set(utils.HeaderETag, etag)
+	if ETagMatches(r.Header.Get(utils.HeaderIfNoneMatch), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	w.Header().Add(utils.HeaderContentType, string(mediaType))
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(body.Bytes())
+	return err
+}
+
+// ETagMatches reports whether any entity tag of the If-None-Match header matches the ETag. Entity tags are compared weakly,
+// as RFC 9110 requires for If-None-Match, and "*" matches any ETag.
+func ETagMatches(ifNoneMatch string, etag string) bool {
+	tags, anyTag, err := parseEntityTags(ifNoneMatch)
+	if err != nil {
+		return false
+	}
+	opaque := strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+	return anyTag || slices.ContainsFunc(tags, func(tag entityTag) bool { return tag.opaque == opaque })
+}
+
+type entityTag struct {
+	opaque string
+	weak   bool
+}
+
+// parseEntityTags reads the comma separated entity tags of a conditional header, e.g. `"3-9f86d0", W/"2"`, or "*".
+func parseEntityTags(header string) ([]entityTag, bool, error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return nil, true, nil
+	}
+	var tags []entityTag
+	for rest := header; ; {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return tags, false, nil
+		}
+		var tag entityTag
+		if after, isWeak := strings.CutPrefix(rest, "W/"); isWeak {
+			tag.weak = true
+			rest = after
+		}
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, false, fmt.Errorf("entity tags have to be quoted, e.g. %s", `"3"`)
+		}
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return nil, false, fmt.Errorf("an entity tag is missing its closing quote")
+		}
+		tag.opaque = rest[1 : end+1]
+		tags = append(tags, tag)
+		rest = rest[end+2:]
+		if rest != "" && !strings.HasPrefix(strings.TrimLeft(rest, " \t"), ",") {
+			return nil, false, fmt.Errorf("entity tags have to be separated by commas")
+		}
+	}
+}
+
+func encodeNegotiated(w io.Writer, mediaType MediaType, v any, rows []types.BankDataCore) error {
+	switch mediaType {
+	case utils.ContentTypeXml:
+		return encodeXml(w, v)
+	case utils.ContentTypeNdjson:
+		return encodeNdjson(w, rows)
+	case utils.ContentTypeCsv:
+		return encodeCsv(w, rows)
+	default:
+		return json.NewEncoder(w).Encode(v)
+	}
+}
+
+func encodeXml(w io.Writer, v any) error {
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+func encodeNdjson(w io.Writer, rows []types.BankDataCore) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeCsv(w io.Writer, rows []types.BankDataCore) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = utils.CsvSeparator
+	if err := writer.Write(utils.CsvBankDataHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(csvBankDataRow(row)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvBankDataRow(bank types.BankDataCore) []string {
+	return []string{bank.SwiftCode, bank.BankName, bank.Address}
+}
@@ -0,0 +1,234 @@
+package api_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/DroppedHard/SWIFT-service/service/api"
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	tests := []struct {
+		Description       string
+		Accept            string
+		ExpectedMediaType api.MediaType
+		ExpectedOk        bool
+	}{
+		{
+			Description:       "Missing Accept header",
+			Accept:            "",
+			ExpectedMediaType: utils.ContentTypeJson,
+			ExpectedOk:        true,
+		},
+		{
+			Description:       "Any media type",
+			Accept:            "*/*",
+			ExpectedMediaType: utils.ContentTypeJson,
+			ExpectedOk:        true,
+		},
+		{
+			Description:       "CSV",
+			Accept:            "text/csv",
+			ExpectedMediaType: utils.ContentTypeCsv,
+			ExpectedOk:        true,
+		},
+		{
+			Description:       "Text wildcard",
+			Accept:            "text/*",
+			ExpectedMediaType: utils.ContentTypeCsv,
+			ExpectedOk:        true,
+		},
+		{
+			Description:       "Highest quality wins",
+			Accept:            "application/json;q=0.5, application/xml;q=0.9, */*;q=0.1",
+			ExpectedMediaType: utils.ContentTypeXml,
+			ExpectedOk:        true,
+		},
+		{
+			Description:       "Earlier media type wins on a tie",
+			Accept:            "application/x-ndjson, text/csv",
+			ExpectedMediaType: utils.ContentTypeNdjson,
+			ExpectedOk:        true,
+		},
+		{
+			Description:       "Unsupported media types skipped",
+			Accept:            "text/html, application/xml;q=0.8",
+			ExpectedMediaType: utils.ContentTypeXml,
+			ExpectedOk:        true,
+		},
+		{
+			Description: "Only unsupported media types",
+			Accept:      "text/html, image/png",
+		},
+		{
+			Description: "Rejected media type",
+			Accept:      "text/csv;q=0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			mediaType, ok := api.NegotiateMediaType(test.Accept)
+
+			assert.Equal(t, test.ExpectedOk, ok)
+			assert.Equal(t, test.ExpectedMediaType, mediaType)
+		})
+	}
+}
+
+func TestWriteNegotiated(t *testing.T) {
+	rows := []types.BankDataCore{
+		{SwiftCode: "ALBPPLPWXXX", BankName: "ALIOR BANK SA", CountryIso2: "PL", IsHeadquarter: true, Address: "LOPUSZANSKA 38D, WARSZAWA"},
+		{SwiftCode: "ALBPPLPWCUS", BankName: "ALIOR BANK SA", CountryIso2: "PL", IsHeadquarter: false, Address: "LOPUSZANSKA; 38D"},
+	}
+	response := types.SwiftCodesResponse{SwiftCodes: rows, Total: 2}
+
+	tests := []struct {
+		Description         string
+		MediaType           api.MediaType
+		ExpectedContentType string
+		ExpectedBody        string
+	}{
+		{
+			Description:         "JSON",
+			MediaType:           utils.ContentTypeJson,
+			ExpectedContentType: utils.ContentTypeJson,
+			ExpectedBody: `{"swiftCodes":[` +
+				`{"address":"LOPUSZANSKA 38D, WARSZAWA","bankName":"ALIOR BANK SA","countryISO2":"PL","isHeadquarter":true,"swiftCode":"ALBPPLPWXXX"},` +
+				`{"address":"LOPUSZANSKA; 38D","bankName":"ALIOR BANK SA","countryISO2":"PL","isHeadquarter":false,"swiftCode":"ALBPPLPWCUS"}` +
+				`],"total":2}` + "\n",
+		},
+		{
+			Description:         "XML",
+			MediaType:           utils.ContentTypeXml,
+			ExpectedContentType: utils.ContentTypeXml,
+			ExpectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<banks><swiftCodes>` +
+				`<bank><address>LOPUSZANSKA 38D, WARSZAWA</address><bankName>ALIOR BANK SA</bankName><countryISO2>PL</countryISO2><isHeadquarter>true</isHeadquarter><swiftCode>ALBPPLPWXXX</swiftCode></bank>` +
+				`<bank><address>LOPUSZANSKA; 38D</address><bankName>ALIOR BANK SA</bankName><countryISO2>PL</countryISO2><isHeadquarter>false</isHeadquarter><swiftCode>ALBPPLPWCUS</swiftCode></bank>` +
+				`</swiftCodes><total>2</total></banks>`,
+		},
+		{
+			Description:         "NDJSON",
+			MediaType:           utils.ContentTypeNdjson,
+			ExpectedContentType: utils.ContentTypeNdjson,
+			ExpectedBody: `{"address":"LOPUSZANSKA 38D, WARSZAWA","bankName":"ALIOR BANK SA","countryISO2":"PL","isHeadquarter":true,"swiftCode":"ALBPPLPWXXX"}` + "\n" +
+				`{"address":"LOPUSZANSKA; 38D","bankName":"ALIOR BANK SA","countryISO2":"PL","isHeadquarter":false,"swiftCode":"ALBPPLPWCUS"}` + "\n",
+		},
+		{
+			Description:         "CSV in the migration layout",
+			MediaType:           utils.ContentTypeCsv,
+			ExpectedContentType: utils.ContentTypeCsv,
+			ExpectedBody: "SWIFT CODE;NAME;ADDRESS\n" +
+				"ALBPPLPWXXX;ALIOR BANK SA;LOPUSZANSKA 38D, WARSZAWA\n" +
+				"ALBPPLPWCUS;ALIOR BANK SA;\"LOPUSZANSKA; 38D\"\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/swift-codes", nil)
+			req = req.WithContext(context.WithValue(req.Context(), reflect.TypeOf(test.MediaType), test.MediaType))
+			rr := httptest.NewRecorder()
+
+			err := api.WriteNegotiated(rr, req, http.StatusOK, response, rows)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, test.ExpectedContentType, rr.Header().Get(utils.HeaderContentType))
+			assert.Equal(t, utils.HeaderAccept, rr.Header().Get(utils.HeaderVary))
+			assert.Equal(t, test.ExpectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestWriteNegotiatedWithETag(t *testing.T) {
+	rows := []types.BankDataCore{
+		{SwiftCode: "ALBPPLPWXXX", BankName: "ALIOR BANK SA", CountryIso2: "PL", IsHeadquarter: true, Address: "LOPUSZANSKA 38D, WARSZAWA"},
+	}
+	response := types.SwiftCodesResponse{SwiftCodes: rows, Total: 1}
+	write := func(mediaType api.MediaType, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/swift-codes/ALBPPLPWXXX", nil)
+		req = req.WithContext(context.WithValue(req.Context(), reflect.TypeOf(mediaType), mediaType))
+		if ifNoneMatch != "" {
+			req.Header.Set(utils.HeaderIfNoneMatch, ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		assert.NoError(t, api.WriteNegotiatedWithETag(rr, req, response, rows))
+		return rr
+	}
+
+	t.Run("ETag is the hash of the body", func(t *testing.T) {
+		rr := write(utils.ContentTypeJson, "")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, fmt.Sprintf(`"%x"`, sha256.Sum256(rr.Body.Bytes())), rr.Header().Get(utils.HeaderETag))
+		assert.Equal(t, utils.ContentTypeJson, rr.Header().Get(utils.HeaderContentType))
+	})
+	t.Run("ETag differs between media types", func(t *testing.T) {
+		assert.NotEqual(t, write(utils.ContentTypeJson, "").Header().Get(utils.HeaderETag), write(utils.ContentTypeXml, "").Header().Get(utils.HeaderETag))
+	})
+	t.Run("Matching If-None-Match", func(t *testing.T) {
+		etag := write(utils.ContentTypeJson, "").Header().Get(utils.HeaderETag)
+
+		rr := write(utils.ContentTypeJson, etag)
+
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Equal(t, etag, rr.Header().Get(utils.HeaderETag))
+		assert.Empty(t, rr.Header().Get(utils.HeaderContentType))
+		assert.Empty(t, rr.Body.String())
+	})
+	t.Run("Stale If-None-Match", func(t *testing.T) {
+		rr := write(utils.ContentTypeJson, `"stale"`)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEmpty(t, rr.Body.String())
+	})
+}
+
+func TestWriteVersionedNegotiated(t *testing.T) {
+	rows := []types.BankDataCore{
+		{SwiftCode: "ALBPPLPWXXX", BankName: "ALIOR BANK SA", CountryIso2: "PL", IsHeadquarter: true, Address: "LOPUSZANSKA 38D, WARSZAWA"},
+	}
+	response := types.BankDataDetails{BankDataCore: rows[0], Version: 3}
+	req := httptest.NewRequest(http.MethodGet, "/swift-codes/ALBPPLPWXXX", nil)
+	rr := httptest.NewRecorder()
+
+	assert.NoError(t, api.WriteVersionedNegotiated(rr, req, response.Version, response, rows))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, fmt.Sprintf(`"3-%x"`, sha256.Sum256(rr.Body.Bytes())), rr.Header().Get(utils.HeaderETag))
+	condition, err := api.ParseIfMatch(rr.Header().Get(utils.HeaderETag))
+	assert.NoError(t, err)
+	assert.True(t, condition.Matches(response.Version))
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		Description string
+		IfNoneMatch string
+		Expected    bool
+	}{
+		{Description: "Same ETag", IfNoneMatch: `"abc"`, Expected: true},
+		{Description: "Weak ETag", IfNoneMatch: `W/"abc"`, Expected: true},
+		{Description: "ETag in a list", IfNoneMatch: `"xyz", "abc"`, Expected: true},
+		{Description: "ETag with a comma in a list", IfNoneMatch: `"x,y", W/"abc"`, Expected: true},
+		{Description: "Any ETag", IfNoneMatch: "*", Expected: true},
+		{Description: "Other ETag", IfNoneMatch: `"xyz"`, Expected: false},
+		{Description: "Unquoted ETag", IfNoneMatch: "abc", Expected: false},
+		{Description: "No header", IfNoneMatch: "", Expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			assert.Equal(t, test.Expected, api.ETagMatches(test.IfNoneMatch, `"abc"`))
+		})
+	}
+}
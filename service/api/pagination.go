@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+)
+
+type PageRequest struct {
+	Limit  int
+	SortBy string
+	After  *PageCursor
+}
+
+// PageCursor points at the last entry of a page. Pages are keyset based, so they stay
+// stable when entries before the cursor are added or removed between requests.
+type PageCursor struct {
+	SortBy    string `json:"sortBy"`
+	SortValue string `json:"value"`
+	SwiftCode string `json:"swiftCode"`
+}
+
+func ParsePageRequest(r *http.Request) (PageRequest, error) {
+	query := r.URL.Query()
+	page := PageRequest{Limit: utils.PageDefaultLimit, SortBy: utils.SortBySwiftCode}
+
+	if value := query.Get(utils.QueryParamLimit); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > utils.PageMaxLimit {
+			return page, fmt.Errorf("%s has to be a number between 1 and %d", utils.QueryParamLimit, utils.PageMaxLimit)
+		}
+		page.Limit = limit
+	}
+	if value := query.Get(utils.QueryParamSortBy); value != "" {
+		if value != utils.SortBySwiftCode && value != utils.SortByBankName {
+			return page, fmt.Errorf("%s has to be one of: %s, %s", utils.QueryParamSortBy, utils.SortBySwiftCode, utils.SortByBankName)
+		}
+		page.SortBy = value
+	}
+	if value := query.Get(utils.QueryParamCursor); value != "" {
+		cursor, err := DecodePageCursor(value)
+		if err != nil {
+			return page, err
+		}
+		if cursor.SortBy != page.SortBy {
+			return page, fmt.Errorf("the %s was issued for %s=%s", utils.QueryParamCursor, utils.QueryParamSortBy, cursor.SortBy)
+		}
+		page.After = cursor
+	}
+	return page, nil
+}
+
+func EncodePageCursor(cursor PageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodePageCursor(value string) (*PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", utils.QueryParamCursor)
+	}
+	var cursor PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.SwiftCode == "" {
+		return nil, fmt.Errorf("invalid %s", utils.QueryParamCursor)
+	}
+	return &cursor, nil
+}
+
+// PaginateBanksData sorts the banks data by the requested field, with the SWIFT code breaking ties,
+// and returns the page after the cursor together with the cursor of the next page, if there is one.
+func PaginateBanksData(banks []types.BankDataCore, page PageRequest) ([]types.BankDataCore, string) {
+	sorted := make([]types.BankDataCore, len(banks))
+	copy(sorted, banks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return pageKeyLess(sortValue(sorted[i], page.SortBy), sorted[i].SwiftCode, sortValue(sorted[j], page.SortBy), sorted[j].SwiftCode)
+	})
+
+	start := 0
+	if page.After != nil {
+		start = sort.Search(len(sorted), func(i int) bool {
+			return pageKeyLess(page.After.SortValue, page.After.SwiftCode, sortValue(sorted[i], page.SortBy), sorted[i].SwiftCode)
+		})
+	}
+	end := min(start+page.Limit, len(sorted))
+	result := sorted[start:end]
+	if end == len(sorted) {
+		return result, ""
+	}
+
+	last := result[len(result)-1]
+	return result, EncodePageCursor(PageCursor{
+		SortBy:    page.SortBy,
+		SortValue: sortValue(last, page.SortBy),
+		SwiftCode: last.SwiftCode,
+	})
+}
+
+func sortValue(bank types.BankDataCore, sortBy string) string {
+	if sortBy == utils.SortByBankName {
+		return bank.BankName
+	}
+	return bank.SwiftCode
+}
+
+func pageKeyLess(value1, swiftCode1, value2, swiftCode2 string) bool {
+	if value1 != value2 {
+		return value1 < value2
+	}
+	return swiftCode1 < swiftCode2
+}
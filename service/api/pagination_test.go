@@ -0,0 +1,112 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DroppedHard/SWIFT-service/service/api"
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	tests := []struct {
+		Description string
+		Query       string
+		Expected    api.PageRequest
+		ExpectedErr string
+	}{
+		{
+			Description: "Defaults",
+			Expected:    api.PageRequest{Limit: utils.PageDefaultLimit, SortBy: utils.SortBySwiftCode},
+		},
+		{
+			Description: "Limit and sort field",
+			Query:       "?limit=10&sortBy=bankName",
+			Expected:    api.PageRequest{Limit: 10, SortBy: utils.SortByBankName},
+		},
+		{
+			Description: "Cursor",
+			Query:       "?cursor=" + api.EncodePageCursor(api.PageCursor{SortBy: utils.SortBySwiftCode, SortValue: "A", SwiftCode: "A"}),
+			Expected: api.PageRequest{
+				Limit:  utils.PageDefaultLimit,
+				SortBy: utils.SortBySwiftCode,
+				After:  &api.PageCursor{SortBy: utils.SortBySwiftCode, SortValue: "A", SwiftCode: "A"},
+			},
+		},
+		{
+			Description: "Limit too big",
+			Query:       fmt.Sprintf("?limit=%d", utils.PageMaxLimit+1),
+			ExpectedErr: "limit has to be a number between 1 and 1000",
+		},
+		{
+			Description: "Limit not a number",
+			Query:       "?limit=ten",
+			ExpectedErr: "limit has to be a number between 1 and 1000",
+		},
+		{
+			Description: "Cursor without SWIFT code",
+			Query:       "?cursor=" + api.EncodePageCursor(api.PageCursor{SortBy: utils.SortBySwiftCode}),
+			ExpectedErr: "invalid cursor",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test"+test.Query, nil)
+			page, err := api.ParsePageRequest(req)
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, page)
+		})
+	}
+}
+
+func TestPaginateBanksData(t *testing.T) {
+	banks := []types.BankDataCore{
+		{SwiftCode: "AAAAPLPWXXX", BankName: "B"},
+		{SwiftCode: "AAAAPLPW001", BankName: "B"},
+		{SwiftCode: "BBBBPLPWXXX", BankName: "A"},
+		{SwiftCode: "CCCCPLPWXXX", BankName: "C"},
+		{SwiftCode: "DDDDPLPWXXX", BankName: "A"},
+	}
+	tests := []struct {
+		SortBy   string
+		Expected []string
+	}{
+		{
+			SortBy:   utils.SortBySwiftCode,
+			Expected: []string{"AAAAPLPW001", "AAAAPLPWXXX", "BBBBPLPWXXX", "CCCCPLPWXXX", "DDDDPLPWXXX"},
+		},
+		{
+			SortBy:   utils.SortByBankName,
+			Expected: []string{"BBBBPLPWXXX", "DDDDPLPWXXX", "AAAAPLPW001", "AAAAPLPWXXX", "CCCCPLPWXXX"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run("Walk all pages sorted by "+test.SortBy, func(t *testing.T) {
+			page := api.PageRequest{Limit: 2, SortBy: test.SortBy}
+			var swiftCodes []string
+			for {
+				result, nextCursor := api.PaginateBanksData(banks, page)
+				assert.LessOrEqual(t, len(result), page.Limit)
+				for _, bank := range result {
+					swiftCodes = append(swiftCodes, bank.SwiftCode)
+				}
+				if nextCursor == "" {
+					break
+				}
+				cursor, err := api.DecodePageCursor(nextCursor)
+				assert.NoError(t, err)
+				page.After = cursor
+			}
+			assert.Equal(t, test.Expected, swiftCodes)
+		})
+	}
+}
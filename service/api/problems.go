@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+)
+
+// ProblemTitles is the catalogue of the problem codes returned in error responses. The codes are stable,
+// so clients should match them instead of the details, which may be reworded.
+var ProblemTitles = map[string]string{
+	utils.ProblemInvalidSwiftCode: "Invalid SWIFT code",
+	utils.ProblemInvalidCountry:   "Invalid country code",
+	utils.ProblemInvalidBankCode:  "Invalid bank code",
+	utils.ProblemInvalidIban:      "Invalid IBAN",
+	utils.ProblemInvalidPathParam: "Invalid path parameter",
+	utils.ProblemInvalidQuery:     "Invalid query parameter",
+	utils.ProblemInvalidBody:      "Invalid request body",
+	utils.ProblemValidationFailed: "Validation failed",
+	utils.ProblemCountryMismatch:  "Country does not match the SWIFT code",
+	utils.ProblemHqMismatch:       "Headquarter flag does not match the SWIFT code",
+	utils.ProblemSwiftCodeChanged: "SWIFT code does not match the path",
+	utils.ProblemDuplicate:        "SWIFT code already exists",
+	utils.ProblemNotFound:         "Resource not found",
+	utils.ProblemNotAcceptable:    "Media type not supported",
+	utils.ProblemStoreUnavailable: "Bank data store unavailable",
+	utils.ProblemInternal:         "Internal server error",
+	utils.ProblemInvalidIdemKey:   "Invalid idempotency key",
+	utils.ProblemIdemKeyReused:    "Idempotency key reused for a different request",
+	utils.ProblemIdemKeyInUse:     "Request with the idempotency key still in progress",
+	utils.ProblemInvalidIfMatch:   "Invalid If-Match header",
+	utils.ProblemPrecondition:     "Bank data version does not match",
+}
+
+// ProblemCode returns the code of the first utils.ProblemError in the error chain, or the fallback code.
+func ProblemCode(err error, fallback string) string {
+	var problemErr utils.ProblemError
+	if errors.As(err, &problemErr) {
+		return problemErr.Code
+	}
+	return fallback
+}
+
+// NewProblem describes the error as an RFC 7807 problem. The field errors of a utils.ValidationError are listed separately
+// and the detail only counts them.
+func NewProblem(status int, code string, err error) types.Problem {
+	problem := types.Problem{
+		Type:   utils.ProblemTypePrefix + code,
+		Title:  ProblemTitles[code],
+		Status: status,
+		Detail: err.Error(),
+		Code:   code,
+	}
+	var validationErr utils.ValidationError
+	if errors.As(err, &validationErr) {
+		for field, message := range validationErr.Errors {
+			problem.Errors = append(problem.Errors, types.FieldError{Field: field, Message: message})
+		}
+		sort.Slice(problem.Errors, func(i, j int) bool {
+			return problem.Errors[i].Field < problem.Errors[j].Field
+		})
+		problem.Detail = validationDetail(len(problem.Errors))
+	}
+	return problem
+}
+
+func validationDetail(fields int) string {
+	if fields == 1 {
+		return "1 field failed validation"
+	}
+	return fmt.Sprintf("%d fields failed validation", fields)
+}
@@ -2,59 +2,165 @@ package swiftCode
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/DroppedHard/SWIFT-service/service/api"
 	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
+	"github.com/jbub/banking/iban"
 )
 
 func (h *SwiftCodeHandler) fetchBankDataBySwiftCode(w http.ResponseWriter, ctx context.Context, swiftCode string) *types.BankDataDetails {
 	bank, err := h.store.FindBankDetailsBySwiftCode(ctx, swiftCode)
 	if err != nil {
-		api.WriteError(w, http.StatusInternalServerError, fmt.Errorf("fetching bank details failed: %v", err))
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("fetching bank details failed: %v", err))
 		return nil
 	}
 	if bank == nil {
-		api.WriteError(w, http.StatusNotFound, fmt.Errorf("the SWIFT code %s was not found", swiftCode))
+		api.WriteError(w, http.StatusNotFound, utils.ProblemNotFound, fmt.Errorf("the SWIFT code %s was not found", swiftCode))
 		return nil
 	}
 	return bank
 }
 
-func (h *SwiftCodeHandler) writeBankHqData(w http.ResponseWriter, ctx context.Context, bank *types.BankDataDetails, swiftCode string) {
-	branches, partialErr := h.store.FindBranchesDataByHqSwiftCode(ctx, swiftCode)
+// resolveNationalBankCode finds the SWIFT code mapped to the national bank code of the IBAN, trying the most specific code first.
+func (h *SwiftCodeHandler) resolveNationalBankCode(w http.ResponseWriter, ctx context.Context, parsed *iban.Iban) *types.IbanBicResponse {
+	countryCode := parsed.CountryCode()
+	for _, nationalBankCode := range utils.NationalBankCodeCandidates(parsed.BankCode(), parsed.BranchCode()) {
+		swiftCode, err := h.store.FindSwiftCodeByNationalBankCode(ctx, countryCode, nationalBankCode)
+		if err != nil {
+			api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("resolving national bank code failed: %v", err))
+			return nil
+		}
+		if swiftCode != "" {
+			return &types.IbanBicResponse{
+				Iban:             parsed.String(),
+				CountryIso2:      countryCode,
+				NationalBankCode: nationalBankCode,
+				SwiftCode:        swiftCode,
+			}
+		}
+	}
+	api.WriteError(w, http.StatusNotFound, utils.ProblemNotFound, fmt.Errorf("no SWIFT code is known for the national bank code %s in %s", parsed.BankCode(), countryCode))
+	return nil
+}
+
+func (h *SwiftCodeHandler) writeBankHqData(w http.ResponseWriter, r *http.Request, bank *types.BankDataDetails, swiftCode string) {
+	branches, partialErr := h.store.FindBranchesDataByHqSwiftCode(r.Context(), swiftCode)
 	bankHq := types.BankHeadquatersResponse{
 		BankDataDetails: *bank,
 		Branches:        branches,
 	}
+	rows := append([]types.BankDataCore{bank.BankDataCore}, branches...)
 	if partialErr != nil {
-		api.WriteJson(w, http.StatusPartialContent, bankHq)
+		api.WriteNegotiated(w, r, http.StatusPartialContent, bankHq, rows)
 		return
 	}
-	api.WriteJson(w, http.StatusOK, bankHq)
+	api.WriteVersionedNegotiated(w, r, bank.Version, bankHq, rows)
 }
 
-func (h *SwiftCodeHandler) fetchBankDataByCountryCode(w http.ResponseWriter, ctx context.Context, countryCode string) *types.CountrySwiftCodesResponse {
-	banks, partialErr := h.store.FindBanksDataByCountryCode(ctx, countryCode)
+// writeBankBranchData writes the branch together with its headquarters summary and the number of the other branches of the headquarters.
+func (h *SwiftCodeHandler) writeBankBranchData(w http.ResponseWriter, r *http.Request, bank *types.BankDataDetails) {
+	ctx := r.Context()
+	hqSwiftCode := utils.HeadquarterSwiftCode(bank.SwiftCode)
+	hq, err := h.store.FindBankDetailsBySwiftCode(ctx, hqSwiftCode)
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("fetching headquarter details failed: %v", err))
+		return
+	}
+	branches, partialErr := h.store.FindBranchesDataByHqSwiftCode(ctx, hqSwiftCode)
+	bankBranch := types.BankBranchResponse{
+		BankDataDetails: *bank,
+		Orphan:          hq == nil,
+	}
+	if hq != nil {
+		bankBranch.Headquarters = &hq.BankDataCore
+	}
+	for _, branch := range branches {
+		if branch.SwiftCode != bank.SwiftCode {
+			bankBranch.SiblingCount++
+		}
+	}
+	rows := []types.BankDataCore{bank.BankDataCore}
+	if partialErr != nil {
+		api.WriteNegotiated(w, r, http.StatusPartialContent, bankBranch, rows)
+		return
+	}
+	api.WriteVersionedNegotiated(w, r, bank.Version, bankBranch, rows)
+}
+
+func (h *SwiftCodeHandler) fetchBankDataByCountryCode(w http.ResponseWriter, r *http.Request, countryCode string, page api.PageRequest) *types.CountrySwiftCodesResponse {
+	banks, partialErr := h.store.FindBanksDataByCountryCode(r.Context(), countryCode)
+	pageBanks, nextCursor := api.PaginateBanksData(banks, page)
 	response := types.CountrySwiftCodesResponse{
 		CountryIso2: countryCode,
 		CountryName: utils.GetCountryNameFromCountryCode(countryCode),
-		SwiftCodes:  banks,
+		SwiftCodes:  pageBanks,
+		Total:       len(banks),
+		NextCursor:  nextCursor,
 	}
 	if partialErr != nil {
-		api.WriteJson(w, http.StatusPartialContent, response)
+		api.WriteNegotiated(w, r, http.StatusPartialContent, response, pageBanks)
 		return nil
 	}
 	return &response
 }
 
+func groupBanksByCountry(bankCode string, banks []types.BankDataCore) types.BankGroupResponse {
+	sorted := slices.Clone(banks)
+	slices.SortFunc(sorted, func(a, b types.BankDataCore) int { return strings.Compare(a.SwiftCode, b.SwiftCode) })
+
+	countries := make(map[string]*types.BankGroupCountry)
+	for _, bank := range sorted {
+		country, ok := countries[bank.CountryIso2]
+		if !ok {
+			country = &types.BankGroupCountry{
+				CountryIso2:  bank.CountryIso2,
+				CountryName:  utils.GetCountryNameFromCountryCode(bank.CountryIso2),
+				Headquarters: []types.BankDataCore{},
+				Branches:     []types.BankDataCore{},
+			}
+			countries[bank.CountryIso2] = country
+		}
+		if bank.IsHeadquarter {
+			country.Headquarters = append(country.Headquarters, bank)
+		} else {
+			country.Branches = append(country.Branches, bank)
+		}
+	}
+
+	response := types.BankGroupResponse{BankCode: bankCode, Total: len(banks)}
+	for _, countryCode := range slices.Sorted(maps.Keys(countries)) {
+		response.Countries = append(response.Countries, *countries[countryCode])
+	}
+	return response
+}
+
+func (h *SwiftCodeHandler) fetchCountriesStats(w http.ResponseWriter, ctx context.Context) []types.CountryStats {
+	countries, err := h.store.FindCountriesStats(ctx)
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to fetch country statistics: %w", err))
+		return nil
+	}
+	if countries == nil {
+		countries = []types.CountryStats{}
+	}
+	return countries
+}
+
 func (h *SwiftCodeHandler) retrieveValidatedPayloadFromContext(w http.ResponseWriter, ctx context.Context) *types.BankDataDetails {
 	payload, ok := ctx.Value(reflect.TypeOf(types.BankDataDetails{})).(types.BankDataDetails)
 	if !ok {
-		api.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to retrieve validated payload"))
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemInternal, fmt.Errorf("failed to retrieve validated payload"))
 		return nil
 	}
 	return &payload
@@ -63,16 +169,373 @@ func (h *SwiftCodeHandler) retrieveValidatedPayloadFromContext(w http.ResponseWr
 func (h *SwiftCodeHandler) checkBankDataExistenceInStorage(w http.ResponseWriter, ctx context.Context, swiftCode string, shouldExist bool) bool {
 	exists, err := h.store.DoesSwiftCodeExist(ctx, swiftCode)
 	if err != nil {
-		api.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to check existence of key %s: %w", swiftCode, err))
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to check existence of key %s: %w", swiftCode, err))
 		return true
 	}
 	if !shouldExist && exists > 0 {
-		api.WriteError(w, http.StatusConflict, fmt.Errorf("the SWIFT code %s already exists", swiftCode))
+		api.WriteError(w, http.StatusConflict, utils.ProblemDuplicate, fmt.Errorf("the SWIFT code %s already exists", swiftCode))
 		return true
 	}
 	if shouldExist && exists == 0 {
-		api.WriteError(w, http.StatusNotFound, fmt.Errorf("the SWIFT code %s does not exist", swiftCode))
+		api.WriteError(w, http.StatusNotFound, utils.ProblemNotFound, fmt.Errorf("the SWIFT code %s does not exist", swiftCode))
+		return true
+	}
+	return false
+}
+
+func (h *SwiftCodeHandler) checkSwiftCodeUnchanged(w http.ResponseWriter, pathSwiftCode string, bodySwiftCode string) bool {
+	if pathSwiftCode != bodySwiftCode {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemSwiftCodeChanged, fmt.Errorf("the SWIFT code %s in the body does not match the SWIFT code %s in the path", bodySwiftCode, pathSwiftCode))
 		return true
 	}
 	return false
 }
+
+func (h *SwiftCodeHandler) readMergePatch(w http.ResponseWriter, r *http.Request) []byte {
+	if r.Body == nil || r.Body == http.NoBody {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidBody, fmt.Errorf("missing request body"))
+		return nil
+	}
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidBody, fmt.Errorf("failed to read request body: %v", err))
+		return nil
+	}
+	return patch
+}
+
+func (h *SwiftCodeHandler) applyMergePatch(w http.ResponseWriter, patch []byte, bank *types.BankDataDetails) *types.BankDataDetails {
+	original, err := json.Marshal(bank)
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemInternal, fmt.Errorf("failed to encode bank data: %v", err))
+		return nil
+	}
+	merged, err := api.MergePatchJson(original, patch)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidBody, err)
+		return nil
+	}
+	var patched types.BankDataDetails
+	if err := json.Unmarshal(merged, &patched); err != nil {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidBody, fmt.Errorf("invalid JSON payload: %v", err))
+		return nil
+	}
+	patched.SwiftCode = utils.NormalizeSwiftCode(patched.SwiftCode)
+	return &patched
+}
+
+func (h *SwiftCodeHandler) updateBankData(w http.ResponseWriter, ctx context.Context, data types.BankDataDetails, expectedVersion int64) {
+	updated, err := h.store.UpdateBankData(ctx, data, expectedVersion)
+	h.writeUpdateResult(w, data.SwiftCode, updated, err)
+}
+
+func (h *SwiftCodeHandler) writeUpdateResult(w http.ResponseWriter, swiftCode string, updated bool, err error) {
+	if isResponseSent := h.checkVersionMismatch(w, err); isResponseSent {
+		return
+	}
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to update data: %w", err))
+		return
+	}
+	if !updated {
+		api.WriteError(w, http.StatusNotFound, utils.ProblemNotFound, fmt.Errorf("the SWIFT code %s does not exist", swiftCode))
+		return
+	}
+	api.WriteMessage(w, http.StatusOK, "bank data succesfully updated")
+}
+
+// mergePatchBankData applies the patch to the bank data it reads and writes the result conditionally on the version it has read.
+// It returns true when the bank data was changed concurrently in between, and the patch has to be applied to it again.
+// With If-Match the client has asked for that version, so the change is reported as 412 instead.
+func (h *SwiftCodeHandler) mergePatchBankData(w http.ResponseWriter, ctx context.Context, swiftCode string, condition types.VersionCondition, patch []byte) bool {
+	bank := h.fetchBankDataBySwiftCode(w, ctx, swiftCode)
+	if bank == nil {
+		return false
+	}
+	if _, isResponseSent := h.checkVersionCondition(w, condition, bank); isResponseSent {
+		return false
+	}
+	patched := h.applyMergePatch(w, patch, bank)
+	if patched == nil {
+		return false
+	}
+	if isResponseSent := h.checkSwiftCodeUnchanged(w, swiftCode, patched.SwiftCode); isResponseSent {
+		return false
+	}
+	if err := api.ValidatePostSwiftCodePayload(ctx, patched); err != nil {
+		api.WriteError(w, http.StatusBadRequest, api.ProblemCode(err, utils.ProblemValidationFailed), fmt.Errorf("validation error: %w", err))
+		return false
+	}
+
+	updated, err := h.store.UpdateBankData(ctx, *patched, bank.Version)
+	var mismatchErr utils.VersionMismatchError
+	if errors.As(err, &mismatchErr) && !condition.Conditional {
+		return true
+	}
+	h.writeUpdateResult(w, swiftCode, updated, err)
+	return false
+}
+
+// parseIfMatchHeader returns the bank data versions the write is conditional on.
+func (h *SwiftCodeHandler) parseIfMatchHeader(w http.ResponseWriter, r *http.Request) (types.VersionCondition, bool) {
+	condition, err := api.ParseIfMatch(r.Header.Get(utils.HeaderIfMatch))
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidIfMatch, err)
+		return condition, true
+	}
+	return condition, false
+}
+
+// checkVersionCondition writes 412 unless the bank data is at a version of the If-Match header. It returns the version
+// the store has to find when writing, so the bank data cannot change between this check and the write - 0 if the write is unconditional.
+func (h *SwiftCodeHandler) checkVersionCondition(w http.ResponseWriter, condition types.VersionCondition, bank *types.BankDataDetails) (int64, bool) {
+	if !condition.Matches(bank.Version) {
+		api.WriteError(w, http.StatusPreconditionFailed, utils.ProblemPrecondition, fmt.Errorf("the bank data of %s is at version %d, which matches none of the %s entity tags", bank.SwiftCode, bank.Version, utils.HeaderIfMatch))
+		return 0, true
+	}
+	if !condition.Conditional {
+		return 0, false
+	}
+	return bank.Version, false
+}
+
+func (h *SwiftCodeHandler) checkVersionMismatch(w http.ResponseWriter, err error) bool {
+	var mismatchErr utils.VersionMismatchError
+	if errors.As(err, &mismatchErr) {
+		api.WriteError(w, http.StatusPreconditionFailed, utils.ProblemPrecondition, mismatchErr)
+		return true
+	}
+	return false
+}
+
+func (h *SwiftCodeHandler) parseAtomicQueryParam(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	value := r.URL.Query().Get(utils.QueryParamAtomic)
+	if value == "" {
+		return false, false
+	}
+	atomic, err := strconv.ParseBool(value)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidQuery, fmt.Errorf("invalid value '%s' of the %s query parameter", value, utils.QueryParamAtomic))
+		return false, true
+	}
+	return atomic, false
+}
+
+func (h *SwiftCodeHandler) retrieveValidatedFilterFromContext(w http.ResponseWriter, ctx context.Context) *types.BankDataFilter {
+	filter, ok := ctx.Value(reflect.TypeOf(types.BankDataFilter{})).(types.BankDataFilter)
+	if !ok {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemInternal, fmt.Errorf("failed to retrieve validated query parameters"))
+		return nil
+	}
+	return &filter
+}
+
+func (h *SwiftCodeHandler) retrieveValidatedExportQueryFromContext(w http.ResponseWriter, ctx context.Context) *types.ExportQuery {
+	export, ok := ctx.Value(reflect.TypeOf(types.ExportQuery{})).(types.ExportQuery)
+	if !ok {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemInternal, fmt.Errorf("failed to retrieve validated query parameters"))
+		return nil
+	}
+	return &export
+}
+
+func (h *SwiftCodeHandler) retrieveValidatedSearchQueryFromContext(w http.ResponseWriter, ctx context.Context) *types.SearchQuery {
+	search, ok := ctx.Value(reflect.TypeOf(types.SearchQuery{})).(types.SearchQuery)
+	if !ok {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemInternal, fmt.Errorf("failed to retrieve validated query parameters"))
+		return nil
+	}
+	return &search
+}
+
+func (h *SwiftCodeHandler) retrieveValidatedAutocompleteQueryFromContext(w http.ResponseWriter, ctx context.Context) *types.AutocompleteQuery {
+	autocomplete, ok := ctx.Value(reflect.TypeOf(types.AutocompleteQuery{})).(types.AutocompleteQuery)
+	if !ok {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemInternal, fmt.Errorf("failed to retrieve validated query parameters"))
+		return nil
+	}
+	return &autocomplete
+}
+
+func (h *SwiftCodeHandler) retrieveValidatedLookupPayloadFromContext(w http.ResponseWriter, ctx context.Context) *types.LookupRequest {
+	payload, ok := ctx.Value(reflect.TypeOf(types.LookupRequest{})).(types.LookupRequest)
+	if !ok {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemInternal, fmt.Errorf("failed to retrieve validated payload"))
+		return nil
+	}
+	return &payload
+}
+
+// validateLookupSwiftCodes reports the invalid SWIFT codes and returns the valid ones, each SWIFT code only once.
+func validateLookupSwiftCodes(swiftCodes []string) (types.LookupResponse, []string) {
+	response := types.LookupResponse{
+		Found:    []types.BankDataDetails{},
+		NotFound: []string{},
+		Invalid:  []string{},
+	}
+	var valid []string
+	seen := make(map[string]bool)
+	for _, swiftCode := range swiftCodes {
+		swiftCode = utils.NormalizeSwiftCode(swiftCode)
+		if seen[swiftCode] {
+			continue
+		}
+		seen[swiftCode] = true
+		if err := api.ValidateInput(swiftCode, "required,"+utils.ValidatorSwiftCode); err != nil {
+			response.Invalid = append(response.Invalid, swiftCode)
+			continue
+		}
+		valid = append(valid, swiftCode)
+	}
+	return response, valid
+}
+
+// lookupBanksDataInStorage sorts the valid SWIFT codes into found and not found ones, in the order of the request.
+// SWIFT codes that the store failed to fetch are reported separately with 206 Partial Content.
+func (h *SwiftCodeHandler) lookupBanksDataInStorage(w http.ResponseWriter, ctx context.Context, response types.LookupResponse, swiftCodes []string) {
+	banks, err := h.store.FindBanksDetailsBySwiftCodes(ctx, swiftCodes)
+	status := http.StatusOK
+	var batchErr utils.BatchFetchError
+	if errors.As(err, &batchErr) {
+		status = http.StatusPartialContent
+		response.Failed = batchErr.FailedKeys()
+	} else if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to look up data: %w", err))
+		return
+	}
+
+	found := make(map[string]types.BankDataDetails, len(banks))
+	for _, bank := range banks {
+		found[bank.SwiftCode] = bank
+	}
+	failed := make(map[string]bool, len(response.Failed))
+	for _, swiftCode := range response.Failed {
+		failed[swiftCode] = true
+	}
+	for _, swiftCode := range swiftCodes {
+		if bank, ok := found[swiftCode]; ok {
+			response.Found = append(response.Found, bank)
+		} else if !failed[swiftCode] {
+			response.NotFound = append(response.NotFound, swiftCode)
+		}
+	}
+	api.WriteJson(w, status, response)
+}
+
+func (h *SwiftCodeHandler) retrieveValidatedBatchPayloadFromContext(w http.ResponseWriter, ctx context.Context) []types.BankDataDetails {
+	payload, ok := ctx.Value(reflect.TypeOf([]types.BankDataDetails{})).([]types.BankDataDetails)
+	if !ok {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemInternal, fmt.Errorf("failed to retrieve validated payload"))
+		return nil
+	}
+	return payload
+}
+
+// validateBatchEntries validates every entry on its own and returns the indexes of the entries that can be stored.
+// Repeated SWIFT codes are reported as conflicts with their first occurrence.
+func validateBatchEntries(ctx context.Context, entries []types.BankDataDetails, atomic bool) (types.BatchCreateResponse, []int) {
+	response := types.BatchCreateResponse{
+		Atomic:  atomic,
+		Results: make([]types.BatchItemResult, len(entries)),
+	}
+	var valid []int
+	seen := make(map[string]int)
+	for i := range entries {
+		err := api.ValidatePostSwiftCodePayload(ctx, &entries[i])
+		response.Results[i] = types.BatchItemResult{Index: i, SwiftCode: entries[i].SwiftCode}
+		if err != nil {
+			response.Results[i].Status = utils.BatchStatusInvalid
+			response.Results[i].Reason = fmt.Sprintf("validation error: %v", err)
+			response.Results[i].Code = api.ProblemCode(err, utils.ProblemValidationFailed)
+			continue
+		}
+		if first, ok := seen[entries[i].SwiftCode]; ok {
+			response.Results[i].Status = utils.BatchStatusConflict
+			response.Results[i].Reason = fmt.Sprintf("the SWIFT code %s is repeated from entry %d", entries[i].SwiftCode, first)
+			continue
+		}
+		seen[entries[i].SwiftCode] = i
+		valid = append(valid, i)
+	}
+	return response, valid
+}
+
+func (h *SwiftCodeHandler) createBankDataBatch(w http.ResponseWriter, ctx context.Context, entries []types.BankDataDetails, response types.BatchCreateResponse, valid []int) {
+	for _, i := range valid {
+		created, err := h.store.CreateBankData(ctx, entries[i])
+		switch {
+		case err != nil:
+			response.Results[i].Status = utils.BatchStatusError
+			response.Results[i].Reason = fmt.Sprintf("failed to add data: %v", err)
+		case !created:
+			response.Results[i].Status = utils.BatchStatusConflict
+			response.Results[i].Reason = fmt.Sprintf("the SWIFT code %s already exists", entries[i].SwiftCode)
+		default:
+			response.Results[i].Status = utils.BatchStatusCreated
+			response.Created++
+		}
+	}
+	if response.Created == len(entries) {
+		api.WriteJson(w, http.StatusCreated, response)
+		return
+	}
+	api.WriteJson(w, http.StatusMultiStatus, response)
+}
+
+func (h *SwiftCodeHandler) createBankDataBatchAtomically(w http.ResponseWriter, ctx context.Context, entries []types.BankDataDetails, response types.BatchCreateResponse, valid []int) {
+	if len(valid) < len(entries) {
+		markSkippedBatchEntries(response, valid)
+		api.WriteJson(w, atomicBatchFailureStatus(response), response)
+		return
+	}
+
+	validEntries := make([]types.BankDataDetails, len(valid))
+	for j, i := range valid {
+		validEntries[j] = entries[i]
+	}
+	conflicts, err := h.store.CreateBankDataBatch(ctx, validEntries)
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to add data: %w", err))
+		return
+	}
+	if len(conflicts) > 0 {
+		conflicting := make(map[string]bool, len(conflicts))
+		for _, swiftCode := range conflicts {
+			conflicting[swiftCode] = true
+		}
+		var notConflicting []int
+		for _, i := range valid {
+			if conflicting[entries[i].SwiftCode] {
+				response.Results[i].Status = utils.BatchStatusConflict
+				response.Results[i].Reason = fmt.Sprintf("the SWIFT code %s already exists", entries[i].SwiftCode)
+			} else {
+				notConflicting = append(notConflicting, i)
+			}
+		}
+		markSkippedBatchEntries(response, notConflicting)
+		api.WriteJson(w, http.StatusConflict, response)
+		return
+	}
+
+	for _, i := range valid {
+		response.Results[i].Status = utils.BatchStatusCreated
+	}
+	response.Created = len(valid)
+	api.WriteJson(w, http.StatusCreated, response)
+}
+
+func markSkippedBatchEntries(response types.BatchCreateResponse, indexes []int) {
+	for _, i := range indexes {
+		response.Results[i].Status = utils.BatchStatusSkipped
+		response.Results[i].Reason = "not added, because other entries of the atomic batch failed"
+	}
+}
+
+func atomicBatchFailureStatus(response types.BatchCreateResponse) int {
+	for _, result := range response.Results {
+		if result.Status == utils.BatchStatusInvalid {
+			return http.StatusBadRequest
+		}
+	}
+	return http.StatusConflict
+}
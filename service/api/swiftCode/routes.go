@@ -2,6 +2,7 @@ package swiftCode
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 
@@ -10,34 +11,58 @@ import (
 	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
 	"github.com/gorilla/mux"
+	"github.com/jbub/banking/iban"
 )
 
 type SwiftCodeHandler struct {
-	store types.BankDataStore
+	store            types.BankDataStore
+	idempotencyStore types.IdempotencyStore
 }
 
-func NewSwiftCodeHandler(store types.BankDataStore) *SwiftCodeHandler {
-	return &SwiftCodeHandler{store: store}
+func NewSwiftCodeHandler(store types.BankDataStore, idempotencyStore types.IdempotencyStore) *SwiftCodeHandler {
+	return &SwiftCodeHandler{store: store, idempotencyStore: idempotencyStore}
 }
 
 func (h *SwiftCodeHandler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/swift-codes/{"+utils.PathParamSwiftCode+"}", middleware.CustomPathParameterValidationMiddleware(api.ValidateSwiftCode)(h.getBankDataBySwiftCode)).Methods("GET")
-	router.HandleFunc("/swift-codes/country/{"+utils.PathParamCountryIso2+"}", middleware.CustomPathParameterValidationMiddleware(api.ValidateCountryCode)(h.getBankDataByCountryCode)).Methods("GET")
-	router.HandleFunc("/swift-codes", middleware.BodyValidationMiddleware(api.ValidatePostSwiftCodePayload)(h.postBankData)).Methods("POST")
-	router.HandleFunc("/swift-codes/{"+utils.PathParamSwiftCode+"}", middleware.CustomPathParameterValidationMiddleware(api.ValidateSwiftCode)(h.deleteBankData)).Methods("DELETE")
+	idempotent := middleware.IdempotencyMiddleware(h.idempotencyStore)
+	router.HandleFunc("/swift-codes/{"+utils.PathParamSwiftCode+"}", middleware.SwiftCodeNormalizationMiddleware(middleware.CustomPathParameterValidationMiddleware(api.ValidateSwiftCode)(middleware.ContentNegotiationMiddleware(h.getBankDataBySwiftCode)))).Methods("GET")
+	router.HandleFunc("/swift-codes/{"+utils.PathParamSwiftCode+"}/validate", h.validateSwiftCode).Methods("GET")
+	router.HandleFunc("/iban/{"+utils.PathParamIban+"}/bic", middleware.CustomPathParameterValidationMiddleware(api.ValidateIban)(h.getBankDataByIban)).Methods("GET")
+	router.HandleFunc("/swift-codes/country/{"+utils.PathParamCountryIso2+"}", middleware.CustomPathParameterValidationMiddleware(api.ValidateCountryCode)(middleware.ContentNegotiationMiddleware(h.getBankDataByCountryCode))).Methods("GET")
+	router.HandleFunc("/banks/{"+utils.PathParamBankCode+"}", middleware.CustomPathParameterValidationMiddleware(api.ValidateBankCode)(h.getBankGroup)).Methods("GET")
+	router.HandleFunc("/countries", h.getCountries).Methods("GET")
+	router.HandleFunc("/countries/{"+utils.PathParamCountryIso2+"}", middleware.CustomPathParameterValidationMiddleware(api.ValidateCountryCode)(h.getCountry)).Methods("GET")
+	router.HandleFunc("/swift-codes", middleware.QueryParameterValidationMiddleware(api.ParseBankDataFilter)(middleware.ContentNegotiationMiddleware(h.getBanksData))).Methods("GET")
+	router.HandleFunc("/export", middleware.QueryParameterValidationMiddleware(api.ParseExportQuery)(h.exportBanksData)).Methods("GET")
+	router.HandleFunc("/search", middleware.QueryParameterValidationMiddleware(api.ParseSearchQuery)(h.searchBanksData)).Methods("GET")
+	router.HandleFunc("/autocomplete", middleware.QueryParameterValidationMiddleware(api.ParseAutocompleteQuery)(h.autocompleteBanksData)).Methods("GET")
+	router.HandleFunc("/swift-codes/lookup", middleware.BodyValidationMiddleware(api.ValidateLookupPayload)(h.lookupBanksData)).Methods("POST")
+	router.HandleFunc("/swift-codes/batch", idempotent(middleware.BodyValidationMiddleware(api.ValidateBatchPayload)(h.postBankDataBatch))).Methods("POST")
+	router.HandleFunc("/swift-codes", idempotent(middleware.BodyValidationMiddleware(api.ValidatePostSwiftCodePayload)(h.postBankData))).Methods("POST")
+	router.HandleFunc("/swift-codes/", idempotent(middleware.BodyValidationMiddleware(api.ValidatePostSwiftCodePayload)(h.postBankData))).Methods("POST")
+	router.HandleFunc("/swift-codes/{"+utils.PathParamSwiftCode+"}", idempotent(middleware.SwiftCodeNormalizationMiddleware(middleware.CustomPathParameterValidationMiddleware(api.ValidateSwiftCode)(middleware.BodyValidationMiddleware(api.ValidatePostSwiftCodePayload)(h.putBankData))))).Methods("PUT")
+	router.HandleFunc("/swift-codes/{"+utils.PathParamSwiftCode+"}", idempotent(middleware.SwiftCodeNormalizationMiddleware(middleware.CustomPathParameterValidationMiddleware(api.ValidateSwiftCode)(h.patchBankData)))).Methods("PATCH")
+	router.HandleFunc("/swift-codes/{"+utils.PathParamSwiftCode+"}", idempotent(middleware.SwiftCodeNormalizationMiddleware(middleware.CustomPathParameterValidationMiddleware(api.ValidateSwiftCode)(h.deleteBankData)))).Methods("DELETE")
 }
 
 // getBankDataBySwiftCode godoc
 // @Summary 		Swift code to bank data
 // @Description 	Use it to fetch bank data by SWIFT code - if it is a HQ it branches will be retrieved too
+// @Description 	A branch comes with a summary of its headquarters and the number of its sibling branches. Branches without a stored headquarters are marked as orphan (see types.BankBranchResponse).
 // @Tags		bank
-// @Produce  	json
-// @Param 		swiftCode 	path 	string 	true 	"Bank swift code"
+// @Produce  	json,xml,application/x-ndjson,text/csv
+// @Param 		swiftCode 	path 	string 	true 	"Bank swift code, BIC8 or BIC11 in any case"
+// @Param 		Accept 	header 	string 	false 	"Response media type - application/json (default), application/xml, application/x-ndjson or text/csv"
+// @Param 		If-None-Match 	header 	string 	false 	"ETag of a cached response - 304 is returned if it is still current"
 // @Success	 	200		{object}	types.BankHeadquatersResponse
 // @Success	 	206		{object}	types.BankHeadquatersResponse
-// @Failure	 	400		{object}	types.ReturnMessage
-// @Failure	 	404		{object}	types.ReturnMessage
-// @Failure	 	500		{object}	types.ReturnMessage
+// @Success	 	304		"Cached response is still current"
+// @Header	 	200,206	{string}	Content-Location	"Canonical URL of the bank data"
+// @Header	 	200,304	{string}	ETag	"Strong entity tag of the response"
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	404		{object}	types.Problem
+// @Failure	 	406		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
 // @Router 		/swift-codes/{swiftCode} [get]
 func (h *SwiftCodeHandler) getBankDataBySwiftCode(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -48,35 +73,259 @@ func (h *SwiftCodeHandler) getBankDataBySwiftCode(w http.ResponseWriter, r *http
 		return
 	}
 	if bank.IsHeadquarter {
-		h.writeBankHqData(w, ctx, bank, swiftCode)
+		h.writeBankHqData(w, r, bank, swiftCode)
 	} else {
-		api.WriteJson(w, http.StatusOK, bank)
+		h.writeBankBranchData(w, r, bank)
+	}
+}
+
+// validateSwiftCode godoc
+// @Summary 		Decode SWIFT code structure
+// @Description 	Use it to check whether the SWIFT code is well-formed and decode it into bank, country, location and branch codes - the SWIFT code does not have to be stored in the system.
+// @Description 	A location code ending with 0 marks a test code and ending with 1 a passive participant. BIC8 codes are normalized to the BIC11 of the primary office.
+// @Tags		bank
+// @Produce  	json
+// @Param 		swiftCode 	path 	string 	true 	"Bank swift code"
+// @Success	 	200		{object}	types.SwiftCodeStructure
+// @Router 		/swift-codes/{swiftCode}/validate [get]
+func (h *SwiftCodeHandler) validateSwiftCode(w http.ResponseWriter, r *http.Request) {
+	swiftCode := mux.Vars(r)[utils.PathParamSwiftCode]
+	api.WriteJson(w, http.StatusOK, api.DecodeSwiftCode(swiftCode))
+}
+
+// getBankDataByIban godoc
+// @Summary 		IBAN to bank data
+// @Description 	Use it to find the BIC of the bank holding the account - the IBAN is validated and its national bank code is resolved to a stored SWIFT code.
+// @Description 	The bank code together with the branch code is tried before the bank code alone. For a branch, its headquarter is included too.
+// @Tags		bank
+// @Produce  	json
+// @Param 		iban 	path 	string 	true 	"IBAN, spaces are ignored"
+// @Success	 	200		{object}	types.IbanBicResponse
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	404		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/iban/{iban}/bic [get]
+func (h *SwiftCodeHandler) getBankDataByIban(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	parsed, err := iban.Parse(utils.CleanIban(mux.Vars(r)[utils.PathParamIban]))
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidIban, fmt.Errorf("invalid IBAN: %w", err))
+		return
+	}
+
+	response := h.resolveNationalBankCode(w, ctx, parsed)
+	if response == nil {
+		return
+	}
+	bank := h.fetchBankDataBySwiftCode(w, ctx, response.SwiftCode)
+	if bank == nil {
+		return
+	}
+	response.Bank = *bank
+	if !bank.IsHeadquarter {
+		response.Headquarter, err = h.store.FindBankDetailsBySwiftCode(ctx, utils.HeadquarterSwiftCode(bank.SwiftCode))
+		if err != nil {
+			api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("fetching headquarter details failed: %v", err))
+			return
+		}
 	}
+	api.WriteJson(w, http.StatusOK, response)
 }
 
 // getBankDataByCountryCode godoc
 // @Summary 		Country code to bank data
-// @Description 	Use it to fetch banks data by country ISO2 code
+// @Description 	Use it to fetch banks data by country ISO2 code. Results are paginated - pass nextCursor from the response as cursor to get the next page.
 // @Tags		bank
-// @Produce  	json
+// @Produce  	json,xml,application/x-ndjson,text/csv
 // @Param 		countryISO2 	path 	string 	true 	"country ISO2 code"
+// @Param 		limit 	query 	int 	false 	"Page size (1-1000, default 100)"
+// @Param 		cursor 	query 	string 	false 	"Cursor of the page to fetch"
+// @Param 		sortBy 	query 	string 	false 	"Sort field (default swiftCode)" Enums(swiftCode, bankName)
+// @Param 		Accept 	header 	string 	false 	"Response media type - application/json (default), application/xml, application/x-ndjson or text/csv"
+// @Param 		If-None-Match 	header 	string 	false 	"ETag of a cached response - 304 is returned if it is still current"
 // @Success	 	200		{object}	types.CountrySwiftCodesResponse
 // @Success	 	206		{object}	types.CountrySwiftCodesResponse
-// @Failure	 	400		{object}	types.ReturnMessage
-// @Failure	 	500		{object}	types.ReturnMessage
+// @Success	 	304		"Cached response is still current"
+// @Header	 	200,304	{string}	ETag	"Strong entity tag of the response"
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	406		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
 // @Router 		/swift-codes/country/{countryISO2} [get]
 func (h *SwiftCodeHandler) getBankDataByCountryCode(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
 	countryCode := mux.Vars(r)[utils.PathParamCountryIso2]
 
-	response := h.fetchBankDataByCountryCode(w, ctx, strings.ToUpper(countryCode))
+	page, err := api.ParsePageRequest(r)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidQuery, err)
+		return
+	}
+	response := h.fetchBankDataByCountryCode(w, r, strings.ToUpper(countryCode), page)
 	if response == nil {
 		return
 	}
 
+	api.WriteNegotiatedWithETag(w, r, response, response.SwiftCodes)
+}
+
+// getBankGroup godoc
+// @Summary 		Bank code to banking group data
+// @Description 	Use it to fetch every headquarter and branch sharing the bank code - the first 4 characters of the SWIFT code - across all countries, grouped by country.
+// @Description 	Countries are ordered by ISO2 code and banks by SWIFT code.
+// @Tags		bank
+// @Produce  	json
+// @Param 		bankCode 	path 	string 	true 	"Bank code - first 4 characters of the SWIFT code"
+// @Success	 	200		{object}	types.BankGroupResponse
+// @Success	 	206		{object}	types.BankGroupResponse
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	404		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/banks/{bankCode} [get]
+func (h *SwiftCodeHandler) getBankGroup(w http.ResponseWriter, r *http.Request) {
+	bankCode := strings.ToUpper(mux.Vars(r)[utils.PathParamBankCode])
+
+	banks, partialErr := h.store.FindBanksData(r.Context(), types.BankDataFilter{BankCode: bankCode})
+	if partialErr != nil && len(banks) == 0 {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to fetch banks: %w", partialErr))
+		return
+	}
+	if len(banks) == 0 {
+		api.WriteError(w, http.StatusNotFound, utils.ProblemNotFound, fmt.Errorf("no SWIFT codes with the bank code %s were found", bankCode))
+		return
+	}
+	response := groupBanksByCountry(bankCode, banks)
+	if partialErr != nil {
+		api.WriteJson(w, http.StatusPartialContent, response)
+		return
+	}
 	api.WriteJson(w, http.StatusOK, response)
 }
 
+// getCountries godoc
+// @Summary 		Countries catalogue
+// @Description 	Use it to list every country with stored SWIFT codes, with the number of all codes, headquarters and branches - ordered by country ISO2 code
+// @Tags		bank
+// @Produce  	json
+// @Success	 	200		{object}	types.CountriesResponse
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/countries [get]
+func (h *SwiftCodeHandler) getCountries(w http.ResponseWriter, r *http.Request) {
+	countries := h.fetchCountriesStats(w, r.Context())
+	if countries == nil {
+		return
+	}
+	api.WriteJson(w, http.StatusOK, types.CountriesResponse{Countries: countries})
+}
+
+// getCountry godoc
+// @Summary 		Country coverage
+// @Description 	Use it to fetch the number of all SWIFT codes, headquarters and branches stored for the country
+// @Tags		bank
+// @Produce  	json
+// @Param 		countryISO2 	path 	string 	true 	"country ISO2 code"
+// @Success	 	200		{object}	types.CountryStats
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	404		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/countries/{countryISO2} [get]
+func (h *SwiftCodeHandler) getCountry(w http.ResponseWriter, r *http.Request) {
+	countryCode := strings.ToUpper(mux.Vars(r)[utils.PathParamCountryIso2])
+
+	countries := h.fetchCountriesStats(w, r.Context())
+	if countries == nil {
+		return
+	}
+	for _, country := range countries {
+		if country.CountryIso2 == countryCode {
+			api.WriteJson(w, http.StatusOK, country)
+			return
+		}
+	}
+	api.WriteError(w, http.StatusNotFound, utils.ProblemNotFound, fmt.Errorf("no SWIFT codes are stored for the country %s", countryCode))
+}
+
+// getBanksData godoc
+// @Summary 		Find bank data by filters
+// @Description 	Use it to fetch banks data matching all of the given filters - country or bankCode is required. Results are paginated like the country listing.
+// @Tags		bank
+// @Produce  	json,xml,application/x-ndjson,text/csv
+// @Param 		country 	query 	string 	false 	"country ISO2 code"
+// @Param 		bankCode 	query 	string 	false 	"Bank code - first 4 characters of the SWIFT code"
+// @Param 		isHeadquarter 	query 	bool 	false 	"Headquarters only (true) or branches only (false)"
+// @Param 		bankName 	query 	string 	false 	"Case-insensitive part of the bank name"
+// @Param 		limit 	query 	int 	false 	"Page size (1-1000, default 100)"
+// @Param 		cursor 	query 	string 	false 	"Cursor of the page to fetch"
+// @Param 		sortBy 	query 	string 	false 	"Sort field (default swiftCode)" Enums(swiftCode, bankName)
+// @Param 		Accept 	header 	string 	false 	"Response media type - application/json (default), application/xml, application/x-ndjson or text/csv"
+// @Success	 	200		{object}	types.SwiftCodesResponse
+// @Success	 	206		{object}	types.SwiftCodesResponse
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	406		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/swift-codes [get]
+func (h *SwiftCodeHandler) getBanksData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter := h.retrieveValidatedFilterFromContext(w, ctx)
+	if filter == nil {
+		return
+	}
+	page, err := api.ParsePageRequest(r)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidQuery, err)
+		return
+	}
+
+	banks, partialErr := h.store.FindBanksData(ctx, *filter)
+	if partialErr != nil && len(banks) == 0 {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to fetch banks: %w", partialErr))
+		return
+	}
+	pageBanks, nextCursor := api.PaginateBanksData(banks, page)
+	response := types.SwiftCodesResponse{
+		SwiftCodes: pageBanks,
+		Total:      len(banks),
+		NextCursor: nextCursor,
+	}
+	if partialErr != nil {
+		api.WriteNegotiated(w, r, http.StatusPartialContent, response, pageBanks)
+		return
+	}
+	api.WriteNegotiated(w, r, http.StatusOK, response, pageBanks)
+}
+
+// exportBanksData godoc
+// @Summary 		Export all bank data
+// @Description 	Use it to download a full or incremental copy of the bank data - records are streamed one per line as NDJSON (with their modification time, if known) or as CSV in the migration app layout. Records are not ordered. Records stored before modification times were tracked are always included. If the store fails mid-stream the connection is aborted, so a complete response always ends cleanly.
+// @Tags		bank
+// @Produce  	application/x-ndjson,text/csv
+// @Param 		format 	query 	string 	false 	"Export format (default ndjson)" Enums(ndjson, csv)
+// @Param 		country 	query 	string 	false 	"country ISO2 code"
+// @Param 		modifiedSince 	query 	string 	false 	"RFC 3339 time - only records modified at or after it are exported"
+// @Success	 	200		{object}	types.BankDataRecord
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/export [get]
+func (h *SwiftCodeHandler) exportBanksData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := h.retrieveValidatedExportQueryFromContext(w, ctx)
+	if query == nil {
+		return
+	}
+
+	writer := api.NewExportWriter(w, query.Format)
+	if err := h.store.ExportBankData(ctx, query.Filter, writer.Write); err != nil {
+		if !writer.Started() {
+			api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to export bank data: %w", err))
+			return
+		}
+		// The status was already sent, so the connection is aborted to keep a truncated export from looking complete.
+		log.Println(fmt.Errorf("export aborted: %w", err))
+		panic(http.ErrAbortHandler)
+	}
+	if err := writer.Close(); err != nil {
+		log.Println(fmt.Errorf("failed to finish export: %w", err))
+	}
+}
+
 // postBankData godoc
 // @Summary 		Add bank data to the system
 // @Description 	Use it to add new bank data - verify data correctiness
@@ -84,10 +333,12 @@ func (h *SwiftCodeHandler) getBankDataByCountryCode(w http.ResponseWriter, r *ht
 // @Accept  	json
 // @Produce  	json
 // @Param 		bankData 	body 	types.BankDataDetails 	true 	"Bank data"
+// @Param 		Idempotency-Key 	header 	string 	false 	"Key of the request - a retry with the same key gets the stored response replayed"
 // @Success	 	201		{object}	types.ReturnMessage
-// @Failure	 	400		{object}	types.ReturnMessage
-// @Failure	 	409		{object}	types.ReturnMessage
-// @Failure	 	500		{object}	types.ReturnMessage
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	409		{object}	types.Problem
+// @Failure	 	422		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
 // @Router 		/swift-codes/ [post]
 func (h *SwiftCodeHandler) postBankData(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -95,40 +346,268 @@ func (h *SwiftCodeHandler) postBankData(w http.ResponseWriter, r *http.Request)
 	if payload == nil {
 		return
 	}
-	isResponseSent := h.checkBankDataExistenceInStorage(w, ctx, payload.SwiftCode, false)
-	if isResponseSent {
+	created, err := h.store.CreateBankData(ctx, *payload)
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to add data: %w", err))
 		return
 	}
-	if err := h.store.SaveBankData(ctx, *payload); err != nil {
-		api.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to add data: %w", err))
+	if !created {
+		api.WriteError(w, http.StatusConflict, utils.ProblemDuplicate, fmt.Errorf("the SWIFT code %s already exists", payload.SwiftCode))
 		return
 	}
+	w.Header().Set(utils.HeaderContentLocation, strings.TrimSuffix(r.URL.Path, "/")+"/"+payload.SwiftCode)
 	api.WriteMessage(w, http.StatusCreated, "bank data succesfully added")
 }
 
+// postBankDataBatch godoc
+// @Summary 		Add multiple bank data entries to the system
+// @Description 	Use it to add many bank data entries at once - every entry is verified separately and gets its own status (created, conflict, invalid).
+// @Description 	With atomic=true either all entries are added in a single transaction or none of them.
+// @Tags		bank
+// @Accept  	json
+// @Produce  	json
+// @Param 		bankData 	body 	[]types.BankDataDetails 	true 	"Bank data entries"
+// @Param 		atomic 	query 	bool 	false 	"Add all entries or none of them"
+// @Param 		Idempotency-Key 	header 	string 	false 	"Key of the request - a retry with the same key gets the stored response replayed"
+// @Success	 	201		{object}	types.BatchCreateResponse
+// @Success	 	207		{object}	types.BatchCreateResponse
+// @Failure	 	400		{object}	types.BatchCreateResponse
+// @Failure	 	409		{object}	types.BatchCreateResponse
+// @Failure	 	422		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/swift-codes/batch [post]
+func (h *SwiftCodeHandler) postBankDataBatch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	atomic, isResponseSent := h.parseAtomicQueryParam(w, r)
+	if isResponseSent {
+		return
+	}
+	entries := h.retrieveValidatedBatchPayloadFromContext(w, ctx)
+	if entries == nil {
+		return
+	}
+
+	response, valid := validateBatchEntries(ctx, entries, atomic)
+	if atomic {
+		h.createBankDataBatchAtomically(w, ctx, entries, response, valid)
+	} else {
+		h.createBankDataBatch(w, ctx, entries, response, valid)
+	}
+}
+
+// lookupBanksData godoc
+// @Summary 		Look up many SWIFT codes at once
+// @Description 	Use it to fetch bank data of up to 1000 SWIFT codes in one request. Every SWIFT code is reported once - as found, not found or invalid.
+// @Tags		bank
+// @Accept  	json
+// @Produce  	json
+// @Param 		lookup 	body 	types.LookupRequest 	true 	"SWIFT codes to look up"
+// @Success	 	200		{object}	types.LookupResponse
+// @Success	 	206		{object}	types.LookupResponse
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/swift-codes/lookup [post]
+func (h *SwiftCodeHandler) lookupBanksData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload := h.retrieveValidatedLookupPayloadFromContext(w, ctx)
+	if payload == nil {
+		return
+	}
+
+	response, valid := validateLookupSwiftCodes(payload.SwiftCodes)
+	if len(valid) == 0 {
+		api.WriteJson(w, http.StatusOK, response)
+		return
+	}
+	h.lookupBanksDataInStorage(w, ctx, response, valid)
+}
+
+// putBankData godoc
+// @Summary 		Replace bank data
+// @Description 	Use it to replace all data of an existing SWIFT code - the SWIFT code itself cannot be changed
+// @Tags		bank
+// @Accept  	json
+// @Produce  	json
+// @Param 		swiftCode 	path 	string 	true 	"Bank swift code, BIC8 or BIC11 in any case"
+// @Param 		bankData 	body 	types.BankDataDetails 	true 	"Bank data"
+// @Param 		Idempotency-Key 	header 	string 	false 	"Key of the request - a retry with the same key gets the stored response replayed"
+// @Param 		If-Match 	header 	string 	false 	"ETag of the bank data read or its version in double quotes, comma separated - the write fails with 412 if the bank data is at another version"
+// @Success	 	200		{object}	types.ReturnMessage
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	404		{object}	types.Problem
+// @Failure	 	409		{object}	types.Problem
+// @Failure	 	412		{object}	types.Problem
+// @Failure	 	422		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/swift-codes/{swiftCode} [put]
+func (h *SwiftCodeHandler) putBankData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	swiftCode := mux.Vars(r)[utils.PathParamSwiftCode]
+	condition, isResponseSent := h.parseIfMatchHeader(w, r)
+	if isResponseSent {
+		return
+	}
+	payload := h.retrieveValidatedPayloadFromContext(w, ctx)
+	if payload == nil {
+		return
+	}
+	if isResponseSent := h.checkSwiftCodeUnchanged(w, swiftCode, payload.SwiftCode); isResponseSent {
+		return
+	}
+	var expectedVersion int64
+	if condition.Conditional {
+		bank := h.fetchBankDataBySwiftCode(w, ctx, swiftCode)
+		if bank == nil {
+			return
+		}
+		if expectedVersion, isResponseSent = h.checkVersionCondition(w, condition, bank); isResponseSent {
+			return
+		}
+	}
+	h.updateBankData(w, ctx, *payload, expectedVersion)
+}
+
+// patchBankData godoc
+// @Summary 		Partially update bank data
+// @Description 	Use it to update chosen fields of an existing SWIFT code with a JSON Merge Patch - the SWIFT code itself cannot be changed
+// @Tags		bank
+// @Accept  	json
+// @Accept  	application/merge-patch+json
+// @Produce  	json
+// @Param 		swiftCode 	path 	string 	true 	"Bank swift code, BIC8 or BIC11 in any case"
+// @Param 		patch 	body 	object 	true 	"JSON Merge Patch of the bank data"
+// @Param 		Idempotency-Key 	header 	string 	false 	"Key of the request - a retry with the same key gets the stored response replayed"
+// @Param 		If-Match 	header 	string 	false 	"ETag of the bank data read or its version in double quotes, comma separated - the write fails with 412 if the bank data is at another version"
+// @Success	 	200		{object}	types.ReturnMessage
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	404		{object}	types.Problem
+// @Failure	 	409		{object}	types.Problem
+// @Failure	 	412		{object}	types.Problem
+// @Failure	 	422		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/swift-codes/{swiftCode} [patch]
+func (h *SwiftCodeHandler) patchBankData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	swiftCode := mux.Vars(r)[utils.PathParamSwiftCode]
+	condition, isResponseSent := h.parseIfMatchHeader(w, r)
+	if isResponseSent {
+		return
+	}
+
+	patch := h.readMergePatch(w, r)
+	if patch == nil {
+		return
+	}
+	for attempt := 0; attempt < utils.PatchMaxAttempts; attempt++ {
+		if isChanged := h.mergePatchBankData(w, ctx, swiftCode, condition, patch); !isChanged {
+			return
+		}
+	}
+	api.WriteError(w, http.StatusConflict, utils.ProblemEditConflict, fmt.Errorf("the bank data of %s kept changing while the patch was applied, try again", swiftCode))
+}
+
 // deleteBankData godoc
 // @Summary 		Delete bank data from the system
 // @Description 	Use it to delete bank data by SWIFT code
 // @Tags		bank
 // @Produce  	json
-// @Param 		swiftCode 	path 	string 	true 	"Bank swift code"
+// @Param 		swiftCode 	path 	string 	true 	"Bank swift code, BIC8 or BIC11 in any case"
+// @Param 		Idempotency-Key 	header 	string 	false 	"Key of the request - a retry with the same key gets the stored response replayed"
+// @Param 		If-Match 	header 	string 	false 	"ETag of the bank data read or its version in double quotes, comma separated - the write fails with 412 if the bank data is at another version"
 // @Success	 	200		{object}	types.ReturnMessage
-// @Failure	 	400		{object}	types.ReturnMessage
-// @Failure	 	404		{object}	types.ReturnMessage
-// @Failure	 	500		{object}	types.ReturnMessage
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	404		{object}	types.Problem
+// @Failure	 	409		{object}	types.Problem
+// @Failure	 	412		{object}	types.Problem
+// @Failure	 	422		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
 // @Router 		/swift-codes/{swiftCode} [delete]
 func (h *SwiftCodeHandler) deleteBankData(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	swiftCode := mux.Vars(r)[utils.PathParamSwiftCode]
-
-	isResponseSent := h.checkBankDataExistenceInStorage(w, ctx, swiftCode, true)
+	condition, isResponseSent := h.parseIfMatchHeader(w, r)
 	if isResponseSent {
 		return
 	}
 
-	if err := h.store.DeleteBankData(ctx, swiftCode); err != nil {
-		api.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to delete data: %w", err))
+	var expectedVersion int64
+	if condition.Conditional {
+		bank := h.fetchBankDataBySwiftCode(w, ctx, swiftCode)
+		if bank == nil {
+			return
+		}
+		if expectedVersion, isResponseSent = h.checkVersionCondition(w, condition, bank); isResponseSent {
+			return
+		}
+	} else if isResponseSent = h.checkBankDataExistenceInStorage(w, ctx, swiftCode, true); isResponseSent {
+		return
+	}
+
+	err := h.store.DeleteBankData(ctx, swiftCode, expectedVersion)
+	if isResponseSent := h.checkVersionMismatch(w, err); isResponseSent {
+		return
+	}
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to delete data: %w", err))
 		return
 	}
 	api.WriteMessage(w, http.StatusOK, "bank data succesfully deleted")
 }
+
+// searchBanksData godoc
+// @Summary 		Full-text bank search
+// @Description 	Use it to search banks by words of their name and address. Matching ignores case and accents, and results are ranked by relevance - name matches weigh more than address matches.
+// @Tags		bank
+// @Produce  	json
+// @Param 		q 	query 	string 	true 	"Search query"
+// @Param 		limit 	query 	int 	false 	"Maximum number of results (1-100, default 20)"
+// @Success	 	200		{object}	types.SearchResponse
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/search [get]
+func (h *SwiftCodeHandler) searchBanksData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	search := h.retrieveValidatedSearchQueryFromContext(w, ctx)
+	if search == nil {
+		return
+	}
+
+	results, err := h.store.SearchBankData(ctx, search.Terms, search.Limit)
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to search banks: %w", err))
+		return
+	}
+	if results == nil {
+		results = []types.SearchResult{}
+	}
+	api.WriteJson(w, http.StatusOK, types.SearchResponse{Query: search.Query, Results: results})
+}
+
+// autocompleteBanksData godoc
+// @Summary 		Bank typeahead
+// @Description 	Use it to suggest banks whose SWIFT code or bank name starts with the prefix. Matching ignores case, accents and punctuation, and headquarters are listed before branches.
+// @Tags		bank
+// @Produce  	json
+// @Param 		prefix 	query 	string 	true 	"Beginning of the SWIFT code or bank name"
+// @Param 		limit 	query 	int 	false 	"Maximum number of results (1-50, default 10)"
+// @Success	 	200		{object}	types.AutocompleteResponse
+// @Failure	 	400		{object}	types.Problem
+// @Failure	 	500		{object}	types.Problem
+// @Router 		/autocomplete [get]
+func (h *SwiftCodeHandler) autocompleteBanksData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	autocomplete := h.retrieveValidatedAutocompleteQueryFromContext(w, ctx)
+	if autocomplete == nil {
+		return
+	}
+
+	banks, err := h.store.AutocompleteBankData(ctx, autocomplete.Prefix, autocomplete.Limit)
+	if err != nil {
+		api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, fmt.Errorf("failed to autocomplete: %w", err))
+		return
+	}
+	if banks == nil {
+		banks = []types.BankDataCore{}
+	}
+	api.WriteJson(w, http.StatusOK, types.AutocompleteResponse{Prefix: autocomplete.Prefix, Results: banks})
+}
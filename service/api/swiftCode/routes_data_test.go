@@ -3,7 +3,9 @@ package swiftCode_test
 import (
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/DroppedHard/SWIFT-service/service/api"
 	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
 )
@@ -47,24 +49,6 @@ var GetBankDataBySwiftCodePositiveTestCases = []GetSwiftCodeTestCase{
 			},
 		},
 	},
-	{
-		Description:   "Valid Branch SWIFT Code",
-		SwiftCode:     "ALBPPLPWCUS",
-		IsHeadquarter: false,
-		ExpectedCode:  http.StatusOK,
-		ExpectedData: &types.BankHeadquatersResponse{
-			BankDataDetails: types.BankDataDetails{
-				BankDataCore: types.BankDataCore{
-					SwiftCode:     "ALBPPLPWCUS",
-					BankName:      "Branch 1",
-					CountryIso2:   "PL",
-					IsHeadquarter: false,
-					Address:       "Branch Street 1",
-				},
-				CountryName: utils.GetCountryNameFromCountryCode("PL"),
-			},
-		},
-	},
 	{
 		Description:   "Valid HQ SWIFT Code (3 branches)",
 		SwiftCode:     "ALBPPLPWXXX",
@@ -165,6 +149,8 @@ var GetBankDataBySwiftCodeNegativeTestCases = []GetSwiftCodeTestCase{
 type CountryCodeTestCase struct {
 	Description       string
 	CountryCode       string
+	Query             string
+	StoreData         []types.BankDataCore
 	ExpectedData      *types.CountrySwiftCodesResponse
 	ExpectedCode      int
 	ErrorIncludes     string
@@ -172,6 +158,37 @@ type CountryCodeTestCase struct {
 	NegativeFindValue []types.BankDataCore
 }
 
+func (testCase CountryCodeTestCase) storeData() []types.BankDataCore {
+	if testCase.StoreData != nil {
+		return testCase.StoreData
+	}
+	return testCase.ExpectedData.SwiftCodes
+}
+
+var countryTestBanks = []types.BankDataCore{
+	{
+		SwiftCode:     "ALBPPLPW001",
+		BankName:      "Branch 3",
+		CountryIso2:   "PL",
+		IsHeadquarter: false,
+		Address:       "Branch Street 1",
+	},
+	{
+		SwiftCode:     "ALBPPLPW002",
+		BankName:      "Branch 2",
+		CountryIso2:   "PL",
+		IsHeadquarter: false,
+		Address:       "Branch Street 2",
+	},
+	{
+		SwiftCode:     "ALBPPLPW003",
+		BankName:      "Branch 1",
+		CountryIso2:   "PL",
+		IsHeadquarter: false,
+		Address:       "Branch Street 3",
+	},
+}
+
 var GetBankDataByCountryCodePositiveTestCases = []CountryCodeTestCase{
 	{
 		Description:  "Valid Country Code (1 result)",
@@ -189,6 +206,7 @@ var GetBankDataByCountryCodePositiveTestCases = []CountryCodeTestCase{
 					Address:       "Branch Street 1",
 				},
 			},
+			Total: 1,
 		},
 	},
 	{
@@ -198,29 +216,74 @@ var GetBankDataByCountryCodePositiveTestCases = []CountryCodeTestCase{
 		ExpectedData: &types.CountrySwiftCodesResponse{
 			CountryIso2: "PL",
 			CountryName: utils.GetCountryNameFromCountryCode("PL"),
-			SwiftCodes: []types.BankDataCore{
-				{
-					SwiftCode:     "ALBPPLPW001",
-					BankName:      "Branch 1",
-					CountryIso2:   "PL",
-					IsHeadquarter: false,
-					Address:       "Branch Street 1",
-				},
-				{
-					SwiftCode:     "ALBPPLPW002",
-					BankName:      "Branch 2",
-					CountryIso2:   "PL",
-					IsHeadquarter: false,
-					Address:       "Branch Street 2",
-				},
-				{
-					SwiftCode:     "ALBPPLPW003",
-					BankName:      "Branch 3",
-					CountryIso2:   "PL",
-					IsHeadquarter: false,
-					Address:       "Branch Street 3",
-				},
-			},
+			SwiftCodes:  countryTestBanks,
+			Total:       3,
+		},
+	},
+	{
+		Description:  "Unordered results are sorted by SWIFT code",
+		CountryCode:  "PL",
+		StoreData:    []types.BankDataCore{countryTestBanks[2], countryTestBanks[0], countryTestBanks[1]},
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.CountrySwiftCodesResponse{
+			CountryIso2: "PL",
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+			SwiftCodes:  countryTestBanks,
+			Total:       3,
+		},
+	},
+	{
+		Description:  "First page",
+		CountryCode:  "PL",
+		Query:        "?limit=2",
+		StoreData:    countryTestBanks,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.CountrySwiftCodesResponse{
+			CountryIso2: "PL",
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+			SwiftCodes:  countryTestBanks[:2],
+			Total:       3,
+			NextCursor:  api.EncodePageCursor(api.PageCursor{SortBy: utils.SortBySwiftCode, SortValue: "ALBPPLPW002", SwiftCode: "ALBPPLPW002"}),
+		},
+	},
+	{
+		Description:  "Last page",
+		CountryCode:  "PL",
+		Query:        "?limit=2&cursor=" + api.EncodePageCursor(api.PageCursor{SortBy: utils.SortBySwiftCode, SortValue: "ALBPPLPW002", SwiftCode: "ALBPPLPW002"}),
+		StoreData:    countryTestBanks,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.CountrySwiftCodesResponse{
+			CountryIso2: "PL",
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+			SwiftCodes:  countryTestBanks[2:],
+			Total:       3,
+		},
+	},
+	{
+		Description:  "Page after a removed entry",
+		CountryCode:  "PL",
+		Query:        "?limit=2&cursor=" + api.EncodePageCursor(api.PageCursor{SortBy: utils.SortBySwiftCode, SortValue: "ALBPPLPW0015", SwiftCode: "ALBPPLPW0015"}),
+		StoreData:    countryTestBanks,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.CountrySwiftCodesResponse{
+			CountryIso2: "PL",
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+			SwiftCodes:  countryTestBanks[1:],
+			Total:       3,
+		},
+	},
+	{
+		Description:  "Sorted by bank name",
+		CountryCode:  "PL",
+		Query:        "?sortBy=bankName&limit=2",
+		StoreData:    countryTestBanks,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.CountrySwiftCodesResponse{
+			CountryIso2: "PL",
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+			SwiftCodes:  []types.BankDataCore{countryTestBanks[2], countryTestBanks[1]},
+			Total:       3,
+			NextCursor:  api.EncodePageCursor(api.PageCursor{SortBy: utils.SortByBankName, SortValue: "Branch 2", SwiftCode: "ALBPPLPW002"}),
 		},
 	},
 }
@@ -247,25 +310,155 @@ var GetBankDataByCountryCodeNegativeTestCases = []CountryCodeTestCase{
 		ExpectedData:  nil,
 		ErrorIncludes: "validation failed on 'countryISO2' tag",
 	},
+	{
+		Description:   "Invalid limit",
+		CountryCode:   "PL",
+		Query:         "?limit=0",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "limit has to be a number between 1 and 1000",
+	},
+	{
+		Description:   "Invalid sort field",
+		CountryCode:   "PL",
+		Query:         "?sortBy=address",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "sortBy has to be one of: swiftCode, bankName",
+	},
+	{
+		Description:   "Invalid cursor",
+		CountryCode:   "PL",
+		Query:         "?cursor=not-a-cursor",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "invalid cursor",
+	},
+	{
+		Description:   "Cursor of another sort field",
+		CountryCode:   "PL",
+		Query:         "?sortBy=bankName&cursor=" + api.EncodePageCursor(api.PageCursor{SortBy: utils.SortBySwiftCode, SortValue: "ALBPPLPW002", SwiftCode: "ALBPPLPW002"}),
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "the cursor was issued for sortBy=swiftCode",
+	},
 	{
 		Description:       "Internal server error (partial return)",
 		CountryCode:       "PL",
 		ExpectedCode:      http.StatusPartialContent,
-		ExpectedData:      nil,
-		NegativeFindValue: nil,
+		NegativeFindValue: []types.BankDataCore{countryTestBanks[0]},
+		NegativeFindError: fmt.Errorf("internal server error message"),
+		ExpectedData: &types.CountrySwiftCodesResponse{
+			CountryIso2: "PL",
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+			SwiftCodes:  []types.BankDataCore{countryTestBanks[0]},
+			Total:       1,
+		},
+	},
+}
+
+type GetBanksDataTestCase struct {
+	Description       string
+	Query             string
+	Filter            types.BankDataFilter
+	StoreData         []types.BankDataCore
+	ExpectedData      *types.SwiftCodesResponse
+	ExpectedCode      int
+	ErrorIncludes     string
+	NegativeFindError error
+}
+
+var filterIsHeadquarter = true
+
+var GetBanksDataTestCases = []GetBanksDataTestCase{
+	{
+		Description:  "Filter by country and headquarters",
+		Query:        "?country=pl&isHeadquarter=true",
+		Filter:       types.BankDataFilter{CountryIso2: "PL", IsHeadquarter: &filterIsHeadquarter},
+		StoreData:    []types.BankDataCore{batchHqBankData.BankDataCore},
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.SwiftCodesResponse{
+			SwiftCodes: []types.BankDataCore{batchHqBankData.BankDataCore},
+			Total:      1,
+		},
+	},
+	{
+		Description:  "Filter by bank code and bank name",
+		Query:        "?bankCode=albp&bankName=Branch",
+		Filter:       types.BankDataFilter{BankCode: "ALBP", BankName: "Branch"},
+		StoreData:    countryTestBanks,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.SwiftCodesResponse{
+			SwiftCodes: countryTestBanks,
+			Total:      3,
+		},
+	},
+	{
+		Description:  "Paginated results",
+		Query:        "?bankCode=ALBP&limit=1&sortBy=bankName",
+		Filter:       types.BankDataFilter{BankCode: "ALBP"},
+		StoreData:    countryTestBanks,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.SwiftCodesResponse{
+			SwiftCodes: countryTestBanks[2:],
+			Total:      3,
+			NextCursor: api.EncodePageCursor(api.PageCursor{SortBy: utils.SortByBankName, SortValue: "Branch 1", SwiftCode: "ALBPPLPW003"}),
+		},
+	},
+	{
+		Description:       "Internal server error (partial return)",
+		Query:             "?country=PL",
+		Filter:            types.BankDataFilter{CountryIso2: "PL"},
+		StoreData:         countryTestBanks[:1],
+		NegativeFindError: fmt.Errorf("internal server error message"),
+		ExpectedCode:      http.StatusPartialContent,
+		ExpectedData: &types.SwiftCodesResponse{
+			SwiftCodes: countryTestBanks[:1],
+			Total:      1,
+		},
+	},
+	{
+		Description:       "Internal server error",
+		Query:             "?country=PL",
+		Filter:            types.BankDataFilter{CountryIso2: "PL"},
 		NegativeFindError: fmt.Errorf("internal server error message"),
-		ErrorIncludes:     "",
+		ExpectedCode:      http.StatusInternalServerError,
+		ErrorIncludes:     "failed to fetch banks",
+	},
+	{
+		Description:   "Missing country and bank code",
+		Query:         "?bankName=Branch",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "at least one of the country and bankCode query parameters is required",
+	},
+	{
+		Description:   "Invalid country",
+		Query:         "?country=XX",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "country: validation failed on 'countryISO2' tag",
+	},
+	{
+		Description:   "Invalid bank code",
+		Query:         "?bankCode=AL1",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "bankCode: validation failed on 'len' tag",
+	},
+	{
+		Description:   "Invalid isHeadquarter",
+		Query:         "?country=PL&isHeadquarter=yes",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "isHeadquarter: validation failed on 'boolean' tag",
+	},
+	{
+		Description:   "Invalid limit",
+		Query:         "?country=PL&limit=-1",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "limit has to be a number between 1 and 1000",
 	},
 }
 
 type PostBankTestCase struct {
-	Description        string
-	BankData           types.BankDataDetails
-	MessageIncludes    string
-	ExpectedCode       int
-	NegativeExistValue int64
-	NegativeExistError error
-	NegativeSaveError  error
+	Description         string
+	BankData            types.BankDataDetails
+	MessageIncludes     string
+	ExpectedCode        int
+	NegativeCreateError error
 }
 
 var PostBankDataPositiveTestCases = []PostBankTestCase{
@@ -306,7 +499,7 @@ var PostBankDataNegativeTestCases = []PostBankTestCase{
 		Description: "Invalid bank data (swift code 1)",
 		BankData: types.BankDataDetails{
 			BankDataCore: types.BankDataCore{
-				SwiftCode:     "ALBPPLPWaaa",
+				SwiftCode:     "ALBPPLPWa-a",
 				BankName:      "Branch Bank",
 				CountryIso2:   "PL",
 				IsHeadquarter: false,
@@ -315,7 +508,7 @@ var PostBankDataNegativeTestCases = []PostBankTestCase{
 			CountryName: utils.GetCountryNameFromCountryCode("PL"),
 		},
 		ExpectedCode:    http.StatusBadRequest,
-		MessageIncludes: "failed on the 'swiftCode' tag",
+		MessageIncludes: "validation failed on 'swiftCode' tag",
 	},
 	{
 		Description: "Invalid bank data (swift code 2)",
@@ -330,7 +523,7 @@ var PostBankDataNegativeTestCases = []PostBankTestCase{
 			CountryName: utils.GetCountryNameFromCountryCode("PL"),
 		},
 		ExpectedCode:    http.StatusBadRequest,
-		MessageIncludes: "failed on the 'swiftCode' tag",
+		MessageIncludes: "validation failed on 'swiftCode' tag",
 	},
 	{
 		Description: "Invalid bank data (country code)",
@@ -345,7 +538,7 @@ var PostBankDataNegativeTestCases = []PostBankTestCase{
 			CountryName: utils.GetCountryNameFromCountryCode("PL"),
 		},
 		ExpectedCode:    http.StatusBadRequest,
-		MessageIncludes: "failed on the 'countryISO2' tag",
+		MessageIncludes: "validation failed on 'countryISO2' tag",
 	},
 	{
 		Description: "Invalid bank data (isHeadquarter)",
@@ -362,22 +555,6 @@ var PostBankDataNegativeTestCases = []PostBankTestCase{
 		ExpectedCode:    http.StatusBadRequest,
 		MessageIncludes: "isHeadquarter value 'false' does not match the swiftCode value 'ALBPPLPWXXX'",
 	},
-	{
-		Description: "Internal server error (exist check)",
-		BankData: types.BankDataDetails{
-			BankDataCore: types.BankDataCore{
-				SwiftCode:     "ALBPPLPWCUS",
-				BankName:      "Branch Bank",
-				CountryIso2:   "PL",
-				IsHeadquarter: false,
-				Address:       "Branch Street 1",
-			},
-			CountryName: utils.GetCountryNameFromCountryCode("PL"),
-		},
-		NegativeExistError: fmt.Errorf("error message"),
-		ExpectedCode:       http.StatusInternalServerError,
-		MessageIncludes:    "error message",
-	},
 	{
 		Description: "Swift code already exists",
 		BankData: types.BankDataDetails{
@@ -390,12 +567,11 @@ var PostBankDataNegativeTestCases = []PostBankTestCase{
 			},
 			CountryName: utils.GetCountryNameFromCountryCode("PL"),
 		},
-		NegativeExistValue: 1,
-		ExpectedCode:       http.StatusConflict,
-		MessageIncludes:    "the SWIFT code ALBPPLPWCUS already exists",
+		ExpectedCode:    http.StatusConflict,
+		MessageIncludes: "the SWIFT code ALBPPLPWCUS already exists",
 	},
 	{
-		Description: "Internal server error (save)",
+		Description: "Internal server error (create)",
 		BankData: types.BankDataDetails{
 			BankDataCore: types.BankDataCore{
 				SwiftCode:     "ALBPPLPWCUS",
@@ -406,69 +582,1270 @@ var PostBankDataNegativeTestCases = []PostBankTestCase{
 			},
 			CountryName: utils.GetCountryNameFromCountryCode("PL"),
 		},
-		NegativeSaveError: fmt.Errorf("error message"),
-		ExpectedCode:      http.StatusInternalServerError,
-		MessageIncludes:   "error message",
+		NegativeCreateError: fmt.Errorf("error message"),
+		ExpectedCode:        http.StatusInternalServerError,
+		MessageIncludes:     "error message",
 	},
 }
 
-type DeleteSwiftCodeTestCase struct {
+type PostBankBatchTestCase struct {
 	Description         string
-	SwiftCode           string
+	Query               string
+	BankData            []types.BankDataDetails
+	RawBody             string
+	Conflicts           []string
+	NegativeCreateError error
 	ExpectedCode        int
+	ExpectedStatuses    []string
+	ExpectedCreated     int
 	MessageIncludes     string
-	NegativeExistValue  int64
-	NegativeExistError  error
-	NegativeDeleteError error
 }
 
-var DeleteBankDataPositiveTestCases = []DeleteSwiftCodeTestCase{
+var batchHqBankData = types.BankDataDetails{
+	BankDataCore: types.BankDataCore{
+		SwiftCode:     "ALBPPLPWXXX",
+		BankName:      "Headquarters Bank",
+		CountryIso2:   "PL",
+		IsHeadquarter: true,
+		Address:       "HQ Street 1",
+	},
+	CountryName: utils.GetCountryNameFromCountryCode("PL"),
+}
+
+var batchBranchBankData = types.BankDataDetails{
+	BankDataCore: types.BankDataCore{
+		SwiftCode:     "ALBPPLPWCUS",
+		BankName:      "Branch Bank",
+		CountryIso2:   "PL",
+		IsHeadquarter: false,
+		Address:       "Branch Street 1",
+	},
+	CountryName: utils.GetCountryNameFromCountryCode("PL"),
+}
+
+var batchInvalidBankData = types.BankDataDetails{
+	BankDataCore: types.BankDataCore{
+		SwiftCode:     "ALBPPLPWXXX",
+		BankName:      "Branch Bank",
+		CountryIso2:   "DE",
+		IsHeadquarter: true,
+		Address:       "Branch Street 1",
+	},
+	CountryName: utils.GetCountryNameFromCountryCode("DE"),
+}
+
+var PostBankDataBatchTestCases = []PostBankBatchTestCase{
 	{
-		Description:     "Valid Swift Code HQ",
-		SwiftCode:       "ALBPPLPWXXX",
-		ExpectedCode:    http.StatusOK,
-		MessageIncludes: "bank data succesfully deleted",
+		Description:      "All entries created",
+		BankData:         []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+		ExpectedCode:     http.StatusCreated,
+		ExpectedStatuses: []string{utils.BatchStatusCreated, utils.BatchStatusCreated},
+		ExpectedCreated:  2,
 	},
 	{
-		Description:     "Valid Swift Code branch",
-		SwiftCode:       "ALBPPLPWCUS",
-		ExpectedCode:    http.StatusOK,
-		MessageIncludes: "bank data succesfully deleted",
+		Description:      "Invalid, repeated and existing entries",
+		BankData:         []types.BankDataDetails{batchHqBankData, batchInvalidBankData, batchBranchBankData, batchHqBankData},
+		Conflicts:        []string{"ALBPPLPWCUS"},
+		ExpectedCode:     http.StatusMultiStatus,
+		ExpectedStatuses: []string{utils.BatchStatusCreated, utils.BatchStatusInvalid, utils.BatchStatusConflict, utils.BatchStatusConflict},
+		ExpectedCreated:  1,
 	},
-}
-var DeleteBankDataNegativeTestCases = []DeleteSwiftCodeTestCase{
 	{
-		Description:     "Invalid Swift Code",
-		SwiftCode:       "ALBPPL__XXX",
-		ExpectedCode:    http.StatusBadRequest,
-		MessageIncludes: "validation failed on 'swiftCode' tag",
+		Description:         "Store error for entries",
+		BankData:            []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+		NegativeCreateError: fmt.Errorf("error message"),
+		ExpectedCode:        http.StatusMultiStatus,
+		ExpectedStatuses:    []string{utils.BatchStatusError, utils.BatchStatusError},
 	},
 	{
-		Description:     "Invalid Swift Code length",
-		SwiftCode:       "ALBPPL__",
-		ExpectedCode:    http.StatusBadRequest,
-		MessageIncludes: "validation failed on 'swiftCode' tag",
+		Description:      "Atomic batch created",
+		Query:            "?atomic=true",
+		BankData:         []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+		ExpectedCode:     http.StatusCreated,
+		ExpectedStatuses: []string{utils.BatchStatusCreated, utils.BatchStatusCreated},
+		ExpectedCreated:  2,
 	},
 	{
-		Description:        "Internal server error (exist check)",
-		SwiftCode:          "ALBPPLPWXXX",
-		ExpectedCode:       http.StatusInternalServerError,
-		NegativeExistError: fmt.Errorf("internal server error message"),
-		MessageIncludes:    "internal server error message",
+		Description:      "Atomic batch with invalid entry",
+		Query:            "?atomic=true",
+		BankData:         []types.BankDataDetails{batchHqBankData, batchInvalidBankData},
+		ExpectedCode:     http.StatusBadRequest,
+		ExpectedStatuses: []string{utils.BatchStatusSkipped, utils.BatchStatusInvalid},
 	},
 	{
-		Description:        "Swift code does not exist",
-		SwiftCode:          "ALBPPLPWXXX",
-		ExpectedCode:       http.StatusNotFound,
-		NegativeExistValue: 0,
-		MessageIncludes:    "the SWIFT code ALBPPLPWXXX does not exist",
+		Description:      "Atomic batch with repeated entry",
+		Query:            "?atomic=true",
+		BankData:         []types.BankDataDetails{batchHqBankData, batchHqBankData},
+		ExpectedCode:     http.StatusConflict,
+		ExpectedStatuses: []string{utils.BatchStatusSkipped, utils.BatchStatusConflict},
 	},
 	{
-		Description:         "Internal server error (delete)",
-		SwiftCode:           "ALBPPLPWXXX",
+		Description:      "Atomic batch with existing entry",
+		Query:            "?atomic=true",
+		BankData:         []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+		Conflicts:        []string{"ALBPPLPWCUS"},
+		ExpectedCode:     http.StatusConflict,
+		ExpectedStatuses: []string{utils.BatchStatusSkipped, utils.BatchStatusConflict},
+	},
+	{
+		Description:         "Atomic batch store error",
+		Query:               "?atomic=true",
+		BankData:            []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+		NegativeCreateError: fmt.Errorf("error message"),
 		ExpectedCode:        http.StatusInternalServerError,
-		NegativeExistValue:  1,
-		NegativeDeleteError: fmt.Errorf("internal server error message"),
-		MessageIncludes:     "internal server error message",
+		MessageIncludes:     "error message",
+	},
+	{
+		Description:     "Invalid atomic value",
+		Query:           "?atomic=maybe",
+		BankData:        []types.BankDataDetails{batchHqBankData},
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "invalid value 'maybe' of the atomic query parameter",
+	},
+	{
+		Description:     "Empty batch",
+		RawBody:         "[]",
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "the batch is empty",
+	},
+	{
+		Description:     "Not an array",
+		RawBody:         `{"swiftCode":"ALBPPLPWXXX"}`,
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "invalid JSON payload",
+	},
+}
+
+type PutBankTestCase struct {
+	Description         string
+	SwiftCode           string
+	BankData            types.BankDataDetails
+	MessageIncludes     string
+	ExpectedCode        int
+	NegativeUpdateError error
+}
+
+var PutBankDataPositiveTestCases = []PutBankTestCase{
+	{
+		Description: "Replace bank HQ data",
+		SwiftCode:   "ALBPPLPWXXX",
+		BankData: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     "ALBPPLPWXXX",
+				BankName:      "Headquarters Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: true,
+				Address:       "New HQ Street 1",
+			},
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+		},
+		ExpectedCode:    http.StatusOK,
+		MessageIncludes: "bank data succesfully updated",
+	},
+}
+
+var PutBankDataNegativeTestCases = []PutBankTestCase{
+	{
+		Description: "Invalid path SWIFT code",
+		SwiftCode:   "ALBPPL__XXX",
+		BankData: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     "ALBPPLPWXXX",
+				BankName:      "Headquarters Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: true,
+				Address:       "HQ Street 1",
+			},
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+		},
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "validation failed on 'swiftCode' tag",
+	},
+	{
+		Description: "Invalid bank data (isHeadquarter)",
+		SwiftCode:   "ALBPPLPWXXX",
+		BankData: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     "ALBPPLPWXXX",
+				BankName:      "Headquarters Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: false,
+				Address:       "HQ Street 1",
+			},
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+		},
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "isHeadquarter value 'false' does not match the swiftCode value 'ALBPPLPWXXX'",
+	},
+	{
+		Description: "SWIFT code changed in body",
+		SwiftCode:   "ALBPPLPWXXX",
+		BankData: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     "ALBPPLPWCUS",
+				BankName:      "Branch Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: false,
+				Address:       "Branch Street 1",
+			},
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+		},
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "the SWIFT code ALBPPLPWCUS in the body does not match the SWIFT code ALBPPLPWXXX in the path",
+	},
+	{
+		Description: "Swift code does not exist",
+		SwiftCode:   "ALBPPLPWXXX",
+		BankData: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     "ALBPPLPWXXX",
+				BankName:      "Headquarters Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: true,
+				Address:       "HQ Street 1",
+			},
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+		},
+		ExpectedCode:    http.StatusNotFound,
+		MessageIncludes: "the SWIFT code ALBPPLPWXXX does not exist",
+	},
+	{
+		Description: "Internal server error (update)",
+		SwiftCode:   "ALBPPLPWXXX",
+		BankData: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     "ALBPPLPWXXX",
+				BankName:      "Headquarters Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: true,
+				Address:       "HQ Street 1",
+			},
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+		},
+		NegativeUpdateError: fmt.Errorf("error message"),
+		ExpectedCode:        http.StatusInternalServerError,
+		MessageIncludes:     "error message",
+	},
+}
+
+type PatchBankTestCase struct {
+	Description         string
+	SwiftCode           string
+	Patch               string
+	ExistingData        *types.BankDataDetails
+	ExpectedData        types.BankDataDetails
+	MessageIncludes     string
+	ExpectedCode        int
+	NegativeFindError   error
+	NegativeUpdateError error
+}
+
+var patchExistingBankData = &types.BankDataDetails{
+	BankDataCore: types.BankDataCore{
+		SwiftCode:     "ALBPPLPWCUS",
+		BankName:      "Branch Bank",
+		CountryIso2:   "PL",
+		IsHeadquarter: false,
+		Address:       "Branch Street 1",
+	},
+	CountryName: utils.GetCountryNameFromCountryCode("PL"),
+}
+
+var PatchBankDataPositiveTestCases = []PatchBankTestCase{
+	{
+		Description:  "Patch address",
+		SwiftCode:    "ALBPPLPWCUS",
+		Patch:        `{"address":"Branch Street 2"}`,
+		ExistingData: patchExistingBankData,
+		ExpectedData: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     "ALBPPLPWCUS",
+				BankName:      "Branch Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: false,
+				Address:       "Branch Street 2",
+			},
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+		},
+		ExpectedCode:    http.StatusOK,
+		MessageIncludes: "bank data succesfully updated",
+	},
+	{
+		Description:  "Patch with unchanged SWIFT code",
+		SwiftCode:    "ALBPPLPWCUS",
+		Patch:        `{"swiftCode":"ALBPPLPWCUS","bankName":"Renamed Bank"}`,
+		ExistingData: patchExistingBankData,
+		ExpectedData: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				SwiftCode:     "ALBPPLPWCUS",
+				BankName:      "Renamed Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: false,
+				Address:       "Branch Street 1",
+			},
+			CountryName: utils.GetCountryNameFromCountryCode("PL"),
+		},
+		ExpectedCode:    http.StatusOK,
+		MessageIncludes: "bank data succesfully updated",
+	},
+}
+
+var PatchBankDataNegativeTestCases = []PatchBankTestCase{
+	{
+		Description:     "Invalid path SWIFT code",
+		SwiftCode:       "ALBPPL__XXX",
+		Patch:           `{"address":"Branch Street 2"}`,
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "validation failed on 'swiftCode' tag",
+	},
+	{
+		Description:     "Swift code not found",
+		SwiftCode:       "ALBPPLPWCUS",
+		Patch:           `{"address":"Branch Street 2"}`,
+		ExpectedCode:    http.StatusNotFound,
+		MessageIncludes: "the SWIFT code ALBPPLPWCUS was not found",
+	},
+	{
+		Description:       "Internal server error (find)",
+		SwiftCode:         "ALBPPLPWCUS",
+		Patch:             `{"address":"Branch Street 2"}`,
+		NegativeFindError: fmt.Errorf("internal server error message"),
+		ExpectedCode:      http.StatusInternalServerError,
+		MessageIncludes:   "internal server error message",
+	},
+	{
+		Description:     "Invalid JSON patch",
+		SwiftCode:       "ALBPPLPWCUS",
+		Patch:           `{"address":`,
+		ExistingData:    patchExistingBankData,
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "invalid merge patch",
+	},
+	{
+		Description:     "SWIFT code changed in patch",
+		SwiftCode:       "ALBPPLPWCUS",
+		Patch:           `{"swiftCode":"ALBPPLPWXXX"}`,
+		ExistingData:    patchExistingBankData,
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "the SWIFT code ALBPPLPWXXX in the body does not match the SWIFT code ALBPPLPWCUS in the path",
+	},
+	{
+		Description:     "Required field removed",
+		SwiftCode:       "ALBPPLPWCUS",
+		Patch:           `{"bankName":null}`,
+		ExistingData:    patchExistingBankData,
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "1 field failed validation",
+	},
+	{
+		Description:     "Country no longer matches SWIFT code",
+		SwiftCode:       "ALBPPLPWCUS",
+		Patch:           `{"countryISO2":"DE"}`,
+		ExistingData:    patchExistingBankData,
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "countryISO2 'DE' does not match the country derived from SWIFT code 'PL'",
+	},
+	{
+		Description:     "Swift code deleted before update",
+		SwiftCode:       "ALBPPLPWCUS",
+		Patch:           `{"address":"Branch Street 2"}`,
+		ExistingData:    patchExistingBankData,
+		ExpectedCode:    http.StatusNotFound,
+		MessageIncludes: "the SWIFT code ALBPPLPWCUS does not exist",
+	},
+	{
+		Description:         "Internal server error (update)",
+		SwiftCode:           "ALBPPLPWCUS",
+		Patch:               `{"address":"Branch Street 2"}`,
+		ExistingData:        patchExistingBankData,
+		NegativeUpdateError: fmt.Errorf("error message"),
+		ExpectedCode:        http.StatusInternalServerError,
+		MessageIncludes:     "error message",
+	},
+}
+
+type DeleteSwiftCodeTestCase struct {
+	Description         string
+	SwiftCode           string
+	ExpectedCode        int
+	MessageIncludes     string
+	NegativeExistValue  int64
+	NegativeExistError  error
+	NegativeDeleteError error
+}
+
+var DeleteBankDataPositiveTestCases = []DeleteSwiftCodeTestCase{
+	{
+		Description:     "Valid Swift Code HQ",
+		SwiftCode:       "ALBPPLPWXXX",
+		ExpectedCode:    http.StatusOK,
+		MessageIncludes: "bank data succesfully deleted",
+	},
+	{
+		Description:     "Valid Swift Code branch",
+		SwiftCode:       "ALBPPLPWCUS",
+		ExpectedCode:    http.StatusOK,
+		MessageIncludes: "bank data succesfully deleted",
+	},
+}
+var DeleteBankDataNegativeTestCases = []DeleteSwiftCodeTestCase{
+	{
+		Description:     "Invalid Swift Code",
+		SwiftCode:       "ALBPPL__XXX",
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "validation failed on 'swiftCode' tag",
+	},
+	{
+		Description:     "Invalid Swift Code length",
+		SwiftCode:       "ALBPPL__",
+		ExpectedCode:    http.StatusBadRequest,
+		MessageIncludes: "validation failed on 'swiftCode' tag",
+	},
+	{
+		Description:        "Internal server error (exist check)",
+		SwiftCode:          "ALBPPLPWXXX",
+		ExpectedCode:       http.StatusInternalServerError,
+		NegativeExistError: fmt.Errorf("internal server error message"),
+		MessageIncludes:    "internal server error message",
+	},
+	{
+		Description:        "Swift code does not exist",
+		SwiftCode:          "ALBPPLPWXXX",
+		ExpectedCode:       http.StatusNotFound,
+		NegativeExistValue: 0,
+		MessageIncludes:    "the SWIFT code ALBPPLPWXXX does not exist",
+	},
+	{
+		Description:         "Internal server error (delete)",
+		SwiftCode:           "ALBPPLPWXXX",
+		ExpectedCode:        http.StatusInternalServerError,
+		NegativeExistValue:  1,
+		NegativeDeleteError: fmt.Errorf("internal server error message"),
+		MessageIncludes:     "internal server error message",
+	},
+}
+
+type SearchBanksDataTestCase struct {
+	Description         string
+	Query               string
+	Terms               []string
+	Limit               int
+	StoreData           []types.SearchResult
+	ExpectedData        *types.SearchResponse
+	ExpectedCode        int
+	ErrorIncludes       string
+	NegativeSearchError error
+}
+
+var searchTestResults = []types.SearchResult{
+	{BankDataCore: countryTestBanks[0], Score: 3},
+	{BankDataCore: countryTestBanks[1], Score: 2},
+}
+
+var SearchBanksDataTestCases = []SearchBanksDataTestCase{
+	{
+		Description:  "Ranked results",
+		Query:        "?q=Br%C3%A1nch%20warsaw",
+		Terms:        []string{"BRANCH", "WARSAW"},
+		Limit:        utils.SearchDefaultLimit,
+		StoreData:    searchTestResults,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.SearchResponse{Query: "Bránch warsaw", Results: searchTestResults},
+	},
+	{
+		Description:  "No matches",
+		Query:        "?q=nothing&limit=5",
+		Terms:        []string{"NOTHING"},
+		Limit:        5,
+		StoreData:    []types.SearchResult(nil),
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.SearchResponse{Query: "nothing", Results: []types.SearchResult{}},
+	},
+	{
+		Description:         "Internal server error",
+		Query:               "?q=branch",
+		Terms:               []string{"BRANCH"},
+		Limit:               utils.SearchDefaultLimit,
+		StoreData:           []types.SearchResult(nil),
+		NegativeSearchError: fmt.Errorf("internal server error message"),
+		ExpectedCode:        http.StatusInternalServerError,
+		ErrorIncludes:       "failed to search banks",
+	},
+	{
+		Description:   "Missing query",
+		Query:         "",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "the q query parameter is required",
+	},
+	{
+		Description:   "Invalid limit",
+		Query:         "?q=branch&limit=0",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "limit has to be a number between 1 and 100",
+	},
+}
+
+type AutocompleteBanksDataTestCase struct {
+	Description               string
+	Query                     string
+	Prefix                    string
+	Limit                     int
+	StoreData                 []types.BankDataCore
+	ExpectedData              *types.AutocompleteResponse
+	ExpectedCode              int
+	ErrorIncludes             string
+	NegativeAutocompleteError error
+}
+
+var AutocompleteBanksDataTestCases = []AutocompleteBanksDataTestCase{
+	{
+		Description:  "Matching banks",
+		Query:        "?prefix=br%C3%A1nch&limit=2",
+		Prefix:       "BRANCH",
+		Limit:        2,
+		StoreData:    countryTestBanks[:2],
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.AutocompleteResponse{Prefix: "BRANCH", Results: countryTestBanks[:2]},
+	},
+	{
+		Description:  "No matches",
+		Query:        "?prefix=zz",
+		Prefix:       "ZZ",
+		Limit:        utils.AutocompleteDefaultLimit,
+		StoreData:    []types.BankDataCore(nil),
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.AutocompleteResponse{Prefix: "ZZ", Results: []types.BankDataCore{}},
+	},
+	{
+		Description:               "Internal server error",
+		Query:                     "?prefix=albp",
+		Prefix:                    "ALBP",
+		Limit:                     utils.AutocompleteDefaultLimit,
+		StoreData:                 []types.BankDataCore(nil),
+		NegativeAutocompleteError: fmt.Errorf("internal server error message"),
+		ExpectedCode:              http.StatusInternalServerError,
+		ErrorIncludes:             "failed to autocomplete",
+	},
+	{
+		Description:   "Missing prefix",
+		Query:         "?prefix=%20-",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "the prefix query parameter has to contain a letter or a digit",
+	},
+	{
+		Description:   "Invalid limit",
+		Query:         "?prefix=albp&limit=51",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "limit has to be a number between 1 and 50",
+	},
+}
+
+type LookupBanksDataTestCase struct {
+	Description       string
+	Body              string
+	StoreSwiftCodes   []string
+	StoreData         []types.BankDataDetails
+	ExpectedData      *types.LookupResponse
+	ExpectedCode      int
+	ErrorIncludes     string
+	NegativeFindError error
+}
+
+var LookupBanksDataTestCases = []LookupBanksDataTestCase{
+	{
+		Description:     "Found, not found and invalid SWIFT codes",
+		Body:            `{"swiftCodes": ["ALBPPLPWCUS", "ALBPPLPWXXX", "ALBPPLPW123", "INVALID", "ALBPPLPWXXX"]}`,
+		StoreSwiftCodes: []string{"ALBPPLPWCUS", "ALBPPLPWXXX", "ALBPPLPW123"},
+		StoreData:       []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+		ExpectedCode:    http.StatusOK,
+		ExpectedData: &types.LookupResponse{
+			Found:    []types.BankDataDetails{batchBranchBankData, batchHqBankData},
+			NotFound: []string{"ALBPPLPW123"},
+			Invalid:  []string{"INVALID"},
+		},
+	},
+	{
+		Description:     "Lowercase and BIC8 SWIFT codes",
+		Body:            `{"swiftCodes": ["albpplpw", " ALBPPLPWCUS", "ALBPPLPWXXX"]}`,
+		StoreSwiftCodes: []string{"ALBPPLPWXXX", "ALBPPLPWCUS"},
+		StoreData:       []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+		ExpectedCode:    http.StatusOK,
+		ExpectedData: &types.LookupResponse{
+			Found:    []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+			NotFound: []string{},
+			Invalid:  []string{},
+		},
+	},
+	{
+		Description:  "Only invalid SWIFT codes",
+		Body:         `{"swiftCodes": ["INVALID", ""]}`,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.LookupResponse{
+			Found:    []types.BankDataDetails{},
+			NotFound: []string{},
+			Invalid:  []string{"INVALID", ""},
+		},
+	},
+	{
+		Description:       "Partially failed lookup",
+		Body:              `{"swiftCodes": ["ALBPPLPWXXX", "ALBPPLPWCUS"]}`,
+		StoreSwiftCodes:   []string{"ALBPPLPWXXX", "ALBPPLPWCUS"},
+		StoreData:         []types.BankDataDetails{batchHqBankData},
+		NegativeFindError: utils.BatchFetchError{Errors: map[string]error{"ALBPPLPWCUS": fmt.Errorf("internal server error message")}},
+		ExpectedCode:      http.StatusPartialContent,
+		ExpectedData: &types.LookupResponse{
+			Found:    []types.BankDataDetails{batchHqBankData},
+			NotFound: []string{},
+			Invalid:  []string{},
+			Failed:   []string{"ALBPPLPWCUS"},
+		},
+	},
+	{
+		Description:       "Internal server error",
+		Body:              `{"swiftCodes": ["ALBPPLPWXXX"]}`,
+		StoreSwiftCodes:   []string{"ALBPPLPWXXX"},
+		StoreData:         []types.BankDataDetails(nil),
+		NegativeFindError: fmt.Errorf("internal server error message"),
+		ExpectedCode:      http.StatusInternalServerError,
+		ErrorIncludes:     "failed to look up data",
+	},
+	{
+		Description:   "No SWIFT codes",
+		Body:          `{"swiftCodes": []}`,
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "no SWIFT codes to look up",
+	},
+	{
+		Description:   "Invalid JSON",
+		Body:          `{"swiftCodes": "ALBPPLPWXXX"}`,
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "invalid JSON payload",
+	},
+}
+
+type ValidateSwiftCodeTestCase struct {
+	Description  string
+	SwiftCode    string
+	ExpectedCode int
+	ExpectedData *types.SwiftCodeStructure
+}
+
+var ValidateSwiftCodeTestCases = []ValidateSwiftCodeTestCase{
+	{
+		Description:  "Valid BIC8 code",
+		SwiftCode:    "BREXPLPW",
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.SwiftCodeStructure{
+			SwiftCode:     "BREXPLPW",
+			Valid:         true,
+			Format:        utils.SwiftFormatBic8,
+			Normalized:    "BREXPLPWXXX",
+			BankCode:      "BREX",
+			CountryIso2:   "PL",
+			CountryName:   "POLAND",
+			LocationCode:  "PW",
+			BranchCode:    utils.BranchSuffix,
+			IsHeadquarter: true,
+		},
+	},
+	{
+		Description:  "Valid test branch code",
+		SwiftCode:    "BREXDEF0ABC",
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.SwiftCodeStructure{
+			SwiftCode:    "BREXDEF0ABC",
+			Valid:        true,
+			Format:       utils.SwiftFormatBic11,
+			Normalized:   "BREXDEF0ABC",
+			BankCode:     "BREX",
+			CountryIso2:  "DE",
+			CountryName:  "GERMANY",
+			LocationCode: "F0",
+			BranchCode:   "ABC",
+			IsTestCode:   true,
+		},
+	},
+	{
+		Description:  "Invalid length",
+		SwiftCode:    "BREXPL",
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &types.SwiftCodeStructure{
+			SwiftCode: "BREXPL",
+			Error:     "swift: invalid length",
+		},
+	},
+}
+
+const testIban = "PL37249000500000000000000000"
+
+type GetBankDataByIbanTestCase struct {
+	Description       string
+	Iban              string
+	NationalBankCodes map[string]string
+	StoredBanks       []types.BankDataDetails
+	NegativeFindError error
+	ExpectedCode      int
+	ExpectedData      *types.IbanBicResponse
+	ErrorIncludes     string
+}
+
+var GetBankDataByIbanTestCases = []GetBankDataByIbanTestCase{
+	{
+		Description:       "Headquarter mapped by bank code",
+		Iban:              testIban,
+		NationalBankCodes: map[string]string{"249": "ALBPPLPWXXX"},
+		StoredBanks:       []types.BankDataDetails{batchHqBankData},
+		ExpectedCode:      http.StatusOK,
+		ExpectedData: &types.IbanBicResponse{
+			Iban:             testIban,
+			CountryIso2:      "PL",
+			NationalBankCode: "249",
+			SwiftCode:        "ALBPPLPWXXX",
+			Bank:             batchHqBankData,
+		},
+	},
+	{
+		Description:       "Branch mapped by bank and branch code",
+		Iban:              "pl37%202490%200050%200000%200000%200000%200000",
+		NationalBankCodes: map[string]string{"2490005": "ALBPPLPWCUS", "249": "ALBPPLPWXXX"},
+		StoredBanks:       []types.BankDataDetails{batchHqBankData, batchBranchBankData},
+		ExpectedCode:      http.StatusOK,
+		ExpectedData: &types.IbanBicResponse{
+			Iban:             testIban,
+			CountryIso2:      "PL",
+			NationalBankCode: "2490005",
+			SwiftCode:        "ALBPPLPWCUS",
+			Bank:             batchBranchBankData,
+			Headquarter:      &batchHqBankData,
+		},
+	},
+	{
+		Description:   "Unknown national bank code",
+		Iban:          testIban,
+		ExpectedCode:  http.StatusNotFound,
+		ErrorIncludes: "no SWIFT code is known for the national bank code 249 in PL",
+	},
+	{
+		Description:       "Mapped SWIFT code not stored",
+		Iban:              testIban,
+		NationalBankCodes: map[string]string{"249": "ALBPPLPWXXX"},
+		ExpectedCode:      http.StatusNotFound,
+		ErrorIncludes:     "the SWIFT code ALBPPLPWXXX was not found",
+	},
+	{
+		Description:       "Internal server error",
+		Iban:              testIban,
+		NegativeFindError: fmt.Errorf("internal server error message"),
+		ExpectedCode:      http.StatusInternalServerError,
+		ErrorIncludes:     "resolving national bank code failed",
+	},
+	{
+		Description:   "Invalid check digit",
+		Iban:          "PL38249000500000000000000000",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "invalid IBAN",
+	},
+}
+
+var testCountriesStats = []types.CountryStats{
+	{CountryIso2: "DE", CountryName: "GERMANY", Total: 3, Headquarters: 1, Branches: 2},
+	{CountryIso2: "PL", CountryName: "POLAND", Total: 5, Headquarters: 2, Branches: 3},
+}
+
+type GetCountriesTestCase struct {
+	Description     string
+	Path            string
+	StoreData       []types.CountryStats
+	NegativeError   error
+	ExpectedCode    int
+	ExpectedData    interface{}
+	ErrorIncludes   string
+	ShouldCallStore bool
+}
+
+var GetCountriesTestCases = []GetCountriesTestCase{
+	{
+		Description:     "All countries",
+		Path:            "/countries",
+		StoreData:       testCountriesStats,
+		ExpectedCode:    http.StatusOK,
+		ExpectedData:    &types.CountriesResponse{Countries: testCountriesStats},
+		ShouldCallStore: true,
+	},
+	{
+		Description:     "No countries",
+		Path:            "/countries",
+		StoreData:       []types.CountryStats(nil),
+		ExpectedCode:    http.StatusOK,
+		ExpectedData:    &types.CountriesResponse{Countries: []types.CountryStats{}},
+		ShouldCallStore: true,
+	},
+	{
+		Description:     "Single country",
+		Path:            "/countries/PL",
+		StoreData:       testCountriesStats,
+		ExpectedCode:    http.StatusOK,
+		ExpectedData:    &testCountriesStats[1],
+		ShouldCallStore: true,
+	},
+	{
+		Description:     "Country without SWIFT codes",
+		Path:            "/countries/FR",
+		StoreData:       testCountriesStats,
+		ExpectedCode:    http.StatusNotFound,
+		ErrorIncludes:   "no SWIFT codes are stored for the country FR",
+		ShouldCallStore: true,
+	},
+	{
+		Description:   "Invalid country code",
+		Path:          "/countries/XX",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "validation failed on 'countryISO2' tag",
+	},
+	{
+		Description:     "Internal server error",
+		Path:            "/countries",
+		StoreData:       []types.CountryStats(nil),
+		NegativeError:   fmt.Errorf("internal server error message"),
+		ExpectedCode:    http.StatusInternalServerError,
+		ErrorIncludes:   "failed to fetch country statistics",
+		ShouldCallStore: true,
+	},
+}
+
+var bankGroupTestBanks = []types.BankDataCore{
+	{SwiftCode: "BREXPLPWXXX", BankName: "BREX BANK SA", CountryIso2: "PL", IsHeadquarter: true, Address: "HQ Street 1"},
+	{SwiftCode: "BREXDEFFBER", BankName: "BREX BANK AG", CountryIso2: "DE", IsHeadquarter: false, Address: "Branch Street 5"},
+	{SwiftCode: "BREXPLPWLOD", BankName: "BREX BANK SA", CountryIso2: "PL", IsHeadquarter: false, Address: "Branch Street 3"},
+	{SwiftCode: "BREXDEFFXXX", BankName: "BREX BANK AG", CountryIso2: "DE", IsHeadquarter: true, Address: "HQ Street 2"},
+}
+
+var bankGroupTestResponse = types.BankGroupResponse{
+	BankCode: "BREX",
+	Total:    4,
+	Countries: []types.BankGroupCountry{
+		{
+			CountryIso2:  "DE",
+			CountryName:  "GERMANY",
+			Headquarters: []types.BankDataCore{bankGroupTestBanks[3]},
+			Branches:     []types.BankDataCore{bankGroupTestBanks[1]},
+		},
+		{
+			CountryIso2:  "PL",
+			CountryName:  "POLAND",
+			Headquarters: []types.BankDataCore{bankGroupTestBanks[0]},
+			Branches:     []types.BankDataCore{bankGroupTestBanks[2]},
+		},
+	},
+}
+
+type GetBankGroupTestCase struct {
+	Description       string
+	BankCode          string
+	Filter            types.BankDataFilter
+	StoreData         []types.BankDataCore
+	NegativeFindError error
+	ExpectedCode      int
+	ExpectedData      *types.BankGroupResponse
+	ErrorIncludes     string
+}
+
+var GetBankGroupTestCases = []GetBankGroupTestCase{
+	{
+		Description:  "Banks grouped by country",
+		BankCode:     "BREX",
+		Filter:       types.BankDataFilter{BankCode: "BREX"},
+		StoreData:    bankGroupTestBanks,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &bankGroupTestResponse,
+	},
+	{
+		Description:  "Lowercase bank code",
+		BankCode:     "brex",
+		Filter:       types.BankDataFilter{BankCode: "BREX"},
+		StoreData:    bankGroupTestBanks,
+		ExpectedCode: http.StatusOK,
+		ExpectedData: &bankGroupTestResponse,
+	},
+	{
+		Description:       "Partially fetched banks",
+		BankCode:          "BREX",
+		Filter:            types.BankDataFilter{BankCode: "BREX"},
+		StoreData:         bankGroupTestBanks,
+		NegativeFindError: utils.BatchFetchError{Errors: map[string]error{"BREXGB2LXXX": fmt.Errorf("internal server error message")}},
+		ExpectedCode:      http.StatusPartialContent,
+		ExpectedData:      &bankGroupTestResponse,
+	},
+	{
+		Description:   "Unknown bank code",
+		BankCode:      "ABCD",
+		Filter:        types.BankDataFilter{BankCode: "ABCD"},
+		StoreData:     []types.BankDataCore(nil),
+		ExpectedCode:  http.StatusNotFound,
+		ErrorIncludes: "no SWIFT codes with the bank code ABCD were found",
+	},
+	{
+		Description:       "Internal server error",
+		BankCode:          "BREX",
+		Filter:            types.BankDataFilter{BankCode: "BREX"},
+		StoreData:         []types.BankDataCore(nil),
+		NegativeFindError: fmt.Errorf("internal server error message"),
+		ExpectedCode:      http.StatusInternalServerError,
+		ErrorIncludes:     "failed to fetch banks",
+	},
+	{
+		Description:   "Bank code with a digit",
+		BankCode:      "BR3X",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "validation failed on 'alpha' tag",
+	},
+	{
+		Description:   "Too long bank code",
+		BankCode:      "BREXX",
+		ExpectedCode:  http.StatusBadRequest,
+		ErrorIncludes: "validation failed on 'len' tag",
+	},
+}
+
+var branchTestBank = types.BankDataDetails{
+	BankDataCore: types.BankDataCore{
+		SwiftCode:     "ALBPPLPWCUS",
+		BankName:      "Branch 1",
+		CountryIso2:   "PL",
+		IsHeadquarter: false,
+		Address:       "Branch Street 1",
+	},
+	CountryName: utils.GetCountryNameFromCountryCode("PL"),
+}
+
+var branchTestHq = types.BankDataDetails{
+	BankDataCore: types.BankDataCore{
+		SwiftCode:     "ALBPPLPWXXX",
+		BankName:      "Headquarters Bank",
+		CountryIso2:   "PL",
+		IsHeadquarter: true,
+		Address:       "HQ Street 1",
+	},
+	CountryName: utils.GetCountryNameFromCountryCode("PL"),
+}
+
+var branchTestBranches = []types.BankDataCore{
+	branchTestBank.BankDataCore,
+	{SwiftCode: "ALBPPLPWLOD", BankName: "Branch 2", CountryIso2: "PL", IsHeadquarter: false, Address: "Branch Street 2"},
+	{SwiftCode: "ALBPPLPWKRK", BankName: "Branch 3", CountryIso2: "PL", IsHeadquarter: false, Address: "Branch Street 3"},
+}
+
+type GetBranchBankDataTestCase struct {
+	Description       string
+	StoreHq           *types.BankDataDetails
+	NegativeHqError   error
+	StoreBranches     []types.BankDataCore
+	NegativeFindError error
+	ExpectedCode      int
+	ExpectedData      *types.BankBranchResponse
+	ErrorIncludes     string
+}
+
+var GetBranchBankDataTestCases = []GetBranchBankDataTestCase{
+	{
+		Description:   "Branch with headquarters and siblings",
+		StoreHq:       &branchTestHq,
+		StoreBranches: branchTestBranches,
+		ExpectedCode:  http.StatusOK,
+		ExpectedData: &types.BankBranchResponse{
+			BankDataDetails: branchTestBank,
+			Headquarters:    &branchTestHq.BankDataCore,
+			SiblingCount:    2,
+		},
+	},
+	{
+		Description:   "Only branch of the headquarters",
+		StoreHq:       &branchTestHq,
+		StoreBranches: branchTestBranches[:1],
+		ExpectedCode:  http.StatusOK,
+		ExpectedData: &types.BankBranchResponse{
+			BankDataDetails: branchTestBank,
+			Headquarters:    &branchTestHq.BankDataCore,
+		},
+	},
+	{
+		Description:   "Orphan branch",
+		StoreBranches: branchTestBranches,
+		ExpectedCode:  http.StatusOK,
+		ExpectedData: &types.BankBranchResponse{
+			BankDataDetails: branchTestBank,
+			SiblingCount:    2,
+			Orphan:          true,
+		},
+	},
+	{
+		Description:       "Partially fetched siblings",
+		StoreHq:           &branchTestHq,
+		StoreBranches:     branchTestBranches[:2],
+		NegativeFindError: utils.BatchFetchError{Errors: map[string]error{"ALBPPLPWKRK": fmt.Errorf("internal server error message")}},
+		ExpectedCode:      http.StatusPartialContent,
+		ExpectedData: &types.BankBranchResponse{
+			BankDataDetails: branchTestBank,
+			Headquarters:    &branchTestHq.BankDataCore,
+			SiblingCount:    1,
+		},
+	},
+	{
+		Description:     "Headquarters internal server error",
+		NegativeHqError: fmt.Errorf("internal server error message"),
+		ExpectedCode:    http.StatusInternalServerError,
+		ErrorIncludes:   "fetching headquarter details failed",
+	},
+}
+
+type ProblemResponseTestCase struct {
+	Description         string
+	Method              string
+	Url                 string
+	Body                string
+	StoreError          error
+	ExpectedCode        int
+	ExpectedProblemCode string
+	ExpectedFields      []string
+}
+
+var ProblemResponseTestCases = []ProblemResponseTestCase{
+	{
+		Description:         "Invalid SWIFT code in the path",
+		Method:              "GET",
+		Url:                 "/swift-codes/ALB)__XAXXX",
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedProblemCode: utils.ProblemInvalidSwiftCode,
+		ExpectedFields:      []string{utils.PathParamSwiftCode},
+	},
+	{
+		Description:         "Invalid country code in the path",
+		Method:              "GET",
+		Url:                 "/swift-codes/country/XX",
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedProblemCode: utils.ProblemInvalidCountry,
+		ExpectedFields:      []string{utils.PathParamCountryIso2},
+	},
+	{
+		Description:         "Invalid query parameters",
+		Method:              "GET",
+		Url:                 "/swift-codes?country=XX&bankCode=B1",
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedProblemCode: utils.ProblemInvalidQuery,
+		ExpectedFields:      []string{utils.QueryParamBankCode, utils.QueryParamCountry},
+	},
+	{
+		Description:         "Unknown SWIFT code",
+		Method:              "GET",
+		Url:                 "/swift-codes/ALBPPLPWXXX",
+		ExpectedCode:        http.StatusNotFound,
+		ExpectedProblemCode: utils.ProblemNotFound,
+	},
+	{
+		Description:         "Store failure",
+		Method:              "GET",
+		Url:                 "/swift-codes/ALBPPLPWXXX",
+		StoreError:          fmt.Errorf("connection refused"),
+		ExpectedCode:        http.StatusInternalServerError,
+		ExpectedProblemCode: utils.ProblemStoreUnavailable,
+	},
+	{
+		Description:         "Malformed JSON body",
+		Method:              "POST",
+		Url:                 "/swift-codes/",
+		Body:                `{"swiftCode":`,
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedProblemCode: utils.ProblemInvalidBody,
+	},
+	{
+		Description:         "Missing fields in the body",
+		Method:              "POST",
+		Url:                 "/swift-codes/",
+		Body:                `{"swiftCode":"ALBPPLPWXXX","isHeadquarter":true}`,
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedProblemCode: utils.ProblemValidationFailed,
+		ExpectedFields:      []string{"address", "bankName", "countryISO2", "countryName"},
+	},
+	{
+		Description:         "Country not matching the SWIFT code",
+		Method:              "POST",
+		Url:                 "/swift-codes/",
+		Body:                `{"swiftCode":"ALBPPLPWXXX","bankName":"Bank","address":"Street 1","countryISO2":"DE","countryName":"GERMANY","isHeadquarter":true}`,
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedProblemCode: utils.ProblemCountryMismatch,
+	},
+	{
+		Description:         "Headquarter flag not matching the SWIFT code",
+		Method:              "POST",
+		Url:                 "/swift-codes/",
+		Body:                `{"swiftCode":"ALBPPLPWCUS","bankName":"Bank","address":"Street 1","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true}`,
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedProblemCode: utils.ProblemHqMismatch,
+	},
+	{
+		Description:         "Duplicate SWIFT code",
+		Method:              "POST",
+		Url:                 "/swift-codes/",
+		Body:                `{"swiftCode":"ALBPPLPWXXX","bankName":"Bank","address":"Street 1","countryISO2":"PL","countryName":"POLAND","isHeadquarter":true}`,
+		ExpectedCode:        http.StatusConflict,
+		ExpectedProblemCode: utils.ProblemDuplicate,
+	},
+}
+
+type NegotiatedResponseTestCase struct {
+	Description         string
+	Url                 string
+	Accept              string
+	ExpectedCode        int
+	ExpectedContentType string
+	ExpectedBody        string
+}
+
+var NegotiatedResponseTestCases = []NegotiatedResponseTestCase{
+	{
+		Description:         "Headquarters with branches as CSV",
+		Url:                 "/swift-codes/ALBPPLPWXXX",
+		Accept:              "text/csv",
+		ExpectedCode:        http.StatusOK,
+		ExpectedContentType: utils.ContentTypeCsv,
+		ExpectedBody: "SWIFT CODE;NAME;ADDRESS\n" +
+			"ALBPPLPWXXX;Headquarters Bank;HQ Street 1\n" +
+			"ALBPPLPWCUS;Branch 1;Branch Street 1\n",
+	},
+	{
+		Description:         "Country as NDJSON",
+		Url:                 "/swift-codes/country/PL",
+		Accept:              "application/x-ndjson",
+		ExpectedCode:        http.StatusOK,
+		ExpectedContentType: utils.ContentTypeNdjson,
+		ExpectedBody: `{"address":"Branch Street 1","bankName":"Branch 1","countryISO2":"PL","isHeadquarter":false,"swiftCode":"ALBPPLPWCUS"}` + "\n" +
+			`{"address":"HQ Street 1","bankName":"Headquarters Bank","countryISO2":"PL","isHeadquarter":true,"swiftCode":"ALBPPLPWXXX"}` + "\n",
+	},
+	{
+		Description:         "Query as XML",
+		Url:                 "/swift-codes?bankCode=ALBP",
+		Accept:              "application/xml",
+		ExpectedCode:        http.StatusOK,
+		ExpectedContentType: utils.ContentTypeXml,
+		ExpectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<banks><swiftCodes>` +
+			`<bank><address>Branch Street 1</address><bankName>Branch 1</bankName><countryISO2>PL</countryISO2><isHeadquarter>false</isHeadquarter><swiftCode>ALBPPLPWCUS</swiftCode></bank>` +
+			`<bank><address>HQ Street 1</address><bankName>Headquarters Bank</bankName><countryISO2>PL</countryISO2><isHeadquarter>true</isHeadquarter><swiftCode>ALBPPLPWXXX</swiftCode></bank>` +
+			`</swiftCodes><total>2</total></banks>`,
+	},
+	{
+		Description:         "Unsupported media type",
+		Url:                 "/swift-codes/ALBPPLPWXXX",
+		Accept:              "text/html",
+		ExpectedCode:        http.StatusNotAcceptable,
+		ExpectedContentType: utils.ContentTypeProblemJson,
+	},
+}
+
+type ExportBanksDataTestCase struct {
+	Description         string
+	Url                 string
+	Filter              types.ExportFilter
+	Records             []types.BankDataRecord
+	StoreError          error
+	ExpectedCode        int
+	ExpectedContentType string
+	ExpectedBody        string
+	ErrorIncludes       string
+}
+
+var exportUpdatedAt = time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+var exportTestRecords = []types.BankDataRecord{
+	{
+		BankDataDetails: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				Address:       "HQ Street 1",
+				BankName:      "Headquarters Bank",
+				CountryIso2:   "PL",
+				IsHeadquarter: true,
+				SwiftCode:     "ALBPPLPWXXX",
+			},
+			CountryName: "POLAND",
+		},
+		UpdatedAt: &exportUpdatedAt,
+	},
+	{
+		BankDataDetails: types.BankDataDetails{
+			BankDataCore: types.BankDataCore{
+				Address:       "Branch Street 1; 2nd floor",
+				BankName:      "Branch 1",
+				CountryIso2:   "PL",
+				IsHeadquarter: false,
+				SwiftCode:     "ALBPPLPWCUS",
+			},
+			CountryName: "POLAND",
+		},
+	},
+}
+
+var ExportBanksDataTestCases = []ExportBanksDataTestCase{
+	{
+		Description:         "NDJSON by default",
+		Url:                 "/export",
+		Records:             exportTestRecords,
+		ExpectedCode:        http.StatusOK,
+		ExpectedContentType: utils.ContentTypeNdjson,
+		ExpectedBody: `{"address":"HQ Street 1","bankName":"Headquarters Bank","countryISO2":"PL","isHeadquarter":true,"swiftCode":"ALBPPLPWXXX","countryName":"POLAND","updatedAt":"2026-03-01T12:00:00Z"}` + "\n" +
+			`{"address":"Branch Street 1; 2nd floor","bankName":"Branch 1","countryISO2":"PL","isHeadquarter":false,"swiftCode":"ALBPPLPWCUS","countryName":"POLAND"}` + "\n",
+	},
+	{
+		Description:         "CSV with filters",
+		Url:                 "/export?format=CSV&country=pl&modifiedSince=2026-02-01T00:00:00%2B01:00",
+		Filter:              types.ExportFilter{CountryIso2: "PL", ModifiedSince: time.Date(2026, time.January, 31, 23, 0, 0, 0, time.UTC)},
+		Records:             exportTestRecords,
+		ExpectedCode:        http.StatusOK,
+		ExpectedContentType: utils.ContentTypeCsv,
+		ExpectedBody: "SWIFT CODE;NAME;ADDRESS\n" +
+			"ALBPPLPWXXX;Headquarters Bank;HQ Street 1\n" +
+			"ALBPPLPWCUS;Branch 1;\"Branch Street 1; 2nd floor\"\n",
+	},
+	{
+		Description:         "Empty CSV export keeps the header",
+		Url:                 "/export?format=csv&country=DE",
+		Filter:              types.ExportFilter{CountryIso2: "DE"},
+		ExpectedCode:        http.StatusOK,
+		ExpectedContentType: utils.ContentTypeCsv,
+		ExpectedBody:        "SWIFT CODE;NAME;ADDRESS\n",
+	},
+	{
+		Description:         "Empty NDJSON export",
+		Url:                 "/export?country=DE",
+		Filter:              types.ExportFilter{CountryIso2: "DE"},
+		ExpectedCode:        http.StatusOK,
+		ExpectedContentType: utils.ContentTypeNdjson,
+	},
+	{
+		Description:         "Unknown format",
+		Url:                 "/export?format=xml",
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedContentType: utils.ContentTypeProblemJson,
+		ErrorIncludes:       "validation failed on 'oneof' tag",
+	},
+	{
+		Description:         "Invalid country",
+		Url:                 "/export?country=P1",
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedContentType: utils.ContentTypeProblemJson,
+		ErrorIncludes:       "validation failed on 'countryISO2' tag",
+	},
+	{
+		Description:         "Invalid modification time",
+		Url:                 "/export?modifiedSince=2026-02-01",
+		ExpectedCode:        http.StatusBadRequest,
+		ExpectedContentType: utils.ContentTypeProblemJson,
+		ErrorIncludes:       "validation failed on 'datetime' tag",
+	},
+	{
+		Description:         "Store failure before the first record",
+		Url:                 "/export",
+		StoreError:          fmt.Errorf("connection refused"),
+		ExpectedCode:        http.StatusInternalServerError,
+		ExpectedContentType: utils.ContentTypeProblemJson,
+		ErrorIncludes:       "failed to export bank data: connection refused",
 	},
 }
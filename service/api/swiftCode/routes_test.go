@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/DroppedHard/SWIFT-service/service/api/swiftCode"
+	"github.com/DroppedHard/SWIFT-service/service/store"
 	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -25,7 +32,7 @@ type RoutesTestSuite struct {
 
 func (suite *RoutesTestSuite) SetupTest() {
 	suite.store = new(mockSwiftCodeStore)
-	handler := swiftCode.NewSwiftCodeHandler(suite.store)
+	handler := swiftCode.NewSwiftCodeHandler(suite.store, store.NewMemoryIdempotencyStore(time.Hour))
 
 	suite.router = mux.NewRouter()
 	handler.RegisterRoutes(suite.router)
@@ -40,7 +47,11 @@ func (suite *RoutesTestSuite) makeRequest(method, url string) *httptest.Response
 
 func (suite *RoutesTestSuite) makePostRequest(url string, body interface{}) *httptest.ResponseRecorder {
 	jsonBody, _ := json.Marshal(body)
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	return suite.makeBodyRequest("POST", url, jsonBody)
+}
+
+func (suite *RoutesTestSuite) makeBodyRequest(method, url string, body []byte) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 	suite.router.ServeHTTP(rr, req)
@@ -60,10 +71,36 @@ func (suite *RoutesTestSuite) assertJSONResponse(rr *httptest.ResponseRecorder,
 
 func (suite *RoutesTestSuite) assertMessageResponse(rr *httptest.ResponseRecorder, expectedCode int, expectedMessage string) {
 	suite.Equal(expectedCode, rr.Code)
-	var errorResponse map[string]string
-	err := json.Unmarshal(rr.Body.Bytes(), &errorResponse)
+	if expectedCode >= http.StatusBadRequest {
+		problem := suite.decodeProblem(rr)
+		details := []string{problem.Detail}
+		for _, fieldErr := range problem.Errors {
+			details = append(details, fieldErr.Field+": "+fieldErr.Message)
+		}
+		suite.Contains(strings.Join(details, "\n"), expectedMessage)
+		return
+	}
+	var messageResponse map[string]string
+	err := json.Unmarshal(rr.Body.Bytes(), &messageResponse)
 	suite.NoError(err)
-	suite.Contains(errorResponse[utils.ResponseMessageField], expectedMessage)
+	suite.Contains(messageResponse[utils.ResponseMessageField], expectedMessage)
+}
+
+func (suite *RoutesTestSuite) assertProblemResponse(rr *httptest.ResponseRecorder, expectedCode int, expectedProblemCode string) types.Problem {
+	suite.Equal(expectedCode, rr.Code)
+	problem := suite.decodeProblem(rr)
+	suite.Equal(expectedProblemCode, problem.Code)
+	suite.Equal(utils.ProblemTypePrefix+expectedProblemCode, problem.Type)
+	suite.Equal(expectedCode, problem.Status)
+	return problem
+}
+
+func (suite *RoutesTestSuite) decodeProblem(rr *httptest.ResponseRecorder) types.Problem {
+	suite.Equal(utils.ContentTypeProblemJson, rr.Header().Get(utils.HeaderContentType))
+	var problem types.Problem
+	err := json.Unmarshal(rr.Body.Bytes(), &problem)
+	suite.NoError(err)
+	return problem
 }
 
 func (suite *RoutesTestSuite) resetMocks() {
@@ -130,6 +167,231 @@ func (suite *RoutesTestSuite) TestGetBankDataBySwiftCode() {
 
 }
 
+func (suite *RoutesTestSuite) TestGetBranchBankData() {
+	for _, testCase := range GetBranchBankDataTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+				mock.Anything,
+				branchTestBank.SwiftCode,
+			).Return(&branchTestBank, nil)
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+				mock.Anything,
+				branchTestHq.SwiftCode,
+			).Return(testCase.StoreHq, testCase.NegativeHqError)
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBranchesDataByHqSwiftCode),
+				mock.Anything,
+				branchTestHq.SwiftCode,
+			).Return(testCase.StoreBranches, testCase.NegativeFindError).Maybe()
+			defer suite.resetMocks()
+
+			rr := suite.makeRequest("GET", "/swift-codes/"+branchTestBank.SwiftCode)
+
+			if testCase.ExpectedData != nil {
+				suite.assertJSONResponse(rr, testCase.ExpectedCode, testCase.ExpectedData)
+			} else {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+			}
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestProblemResponses() {
+	for _, testCase := range ProblemResponseTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+				mock.Anything,
+				mock.Anything,
+			).Return((*types.BankDataDetails)(nil), testCase.StoreError).Maybe()
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.CreateBankData),
+				mock.Anything,
+				mock.Anything,
+			).Return(false, nil).Maybe()
+			defer suite.resetMocks()
+
+			rr := suite.makeBodyRequest(testCase.Method, testCase.Url, []byte(testCase.Body))
+
+			problem := suite.assertProblemResponse(rr, testCase.ExpectedCode, testCase.ExpectedProblemCode)
+			fields := make([]string, len(problem.Errors))
+			for i, fieldErr := range problem.Errors {
+				fields[i] = fieldErr.Field
+			}
+			if testCase.ExpectedFields == nil {
+				suite.Empty(fields)
+			} else {
+				suite.Equal(testCase.ExpectedFields, fields)
+			}
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestNegotiatedResponses() {
+	hq := GetBankDataBySwiftCodePositiveTestCases[0].ExpectedData
+	banks := append([]types.BankDataCore{hq.BankDataCore}, hq.Branches...)
+	for _, testCase := range NegotiatedResponseTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+				mock.Anything,
+				hq.SwiftCode,
+			).Return(&hq.BankDataDetails, nil).Maybe()
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBranchesDataByHqSwiftCode),
+				mock.Anything,
+				hq.SwiftCode,
+			).Return(hq.Branches, nil).Maybe()
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBanksDataByCountryCode),
+				mock.Anything,
+				"PL",
+			).Return(banks, nil).Maybe()
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBanksData),
+				mock.Anything,
+				types.BankDataFilter{BankCode: "ALBP"},
+			).Return(banks, nil).Maybe()
+			defer suite.resetMocks()
+
+			req, _ := http.NewRequest("GET", testCase.Url, nil)
+			req.Header.Set(utils.HeaderAccept, testCase.Accept)
+			rr := httptest.NewRecorder()
+			suite.router.ServeHTTP(rr, req)
+
+			suite.Equal(testCase.ExpectedCode, rr.Code)
+			suite.Equal(testCase.ExpectedContentType, rr.Header().Get(utils.HeaderContentType))
+			if testCase.ExpectedBody != "" {
+				suite.Equal(testCase.ExpectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestExportBanksData() {
+	for _, testCase := range ExportBanksDataTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.ExportBankData),
+				mock.Anything,
+				testCase.Filter,
+			).Return(testCase.Records, testCase.StoreError).Maybe()
+			defer suite.resetMocks()
+
+			req, _ := http.NewRequest("GET", testCase.Url, nil)
+			rr := httptest.NewRecorder()
+			suite.router.ServeHTTP(rr, req)
+
+			suite.Equal(testCase.ExpectedCode, rr.Code)
+			suite.Equal(testCase.ExpectedContentType, rr.Header().Get(utils.HeaderContentType))
+			if testCase.ErrorIncludes != "" {
+				suite.Contains(rr.Body.String(), testCase.ErrorIncludes)
+				return
+			}
+			suite.Equal(testCase.ExpectedBody, rr.Body.String())
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestExportBanksDataAbortedMidStream() {
+	suite.store.On(
+		utils.GetFunctionName(types.BankDataStore.ExportBankData),
+		mock.Anything,
+		types.ExportFilter{},
+	).Return(exportTestRecords, fmt.Errorf("connection reset"))
+	defer suite.resetMocks()
+
+	req, _ := http.NewRequest("GET", "/export", nil)
+	rr := httptest.NewRecorder()
+
+	suite.PanicsWithValue(http.ErrAbortHandler, func() {
+		suite.router.ServeHTTP(rr, req)
+	})
+	suite.Equal(http.StatusOK, rr.Code)
+}
+
+func (suite *RoutesTestSuite) TestGetBankDataByNormalizedSwiftCode() {
+	testCase := GetBankDataBySwiftCodePositiveTestCases[0]
+	suite.store.On(
+		utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+		mock.Anything,
+		testCase.SwiftCode,
+	).Return(&testCase.ExpectedData.BankDataDetails, nil)
+	suite.store.On(
+		utils.GetFunctionName(types.BankDataStore.FindBranchesDataByHqSwiftCode),
+		mock.Anything,
+		testCase.SwiftCode,
+	).Return(testCase.ExpectedData.Branches, nil)
+	defer suite.resetMocks()
+
+	rr := suite.makeRequest("GET", "/swift-codes/"+strings.ToLower(testCase.SwiftCode[:utils.SwiftCodeLength]))
+
+	suite.assertJSONResponse(rr, testCase.ExpectedCode, &testCase.ExpectedData)
+	suite.Equal("/swift-codes/"+testCase.SwiftCode, rr.Header().Get(utils.HeaderContentLocation))
+	suite.store.AssertExpectations(suite.T())
+}
+
+func (suite *RoutesTestSuite) TestContentLocationOnlyOnSuccess() {
+	swiftCode := GetBankDataBySwiftCodePositiveTestCases[0].SwiftCode
+
+	suite.Run("Invalid SWIFT code", func() {
+		rr := suite.makeRequest("GET", "/swift-codes/abc")
+
+		suite.Equal(http.StatusBadRequest, rr.Code)
+		suite.Empty(rr.Header().Get(utils.HeaderContentLocation))
+	})
+
+	suite.Run("Unknown SWIFT code", func() {
+		suite.store.On(utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode), mock.Anything, swiftCode).Return(nil, nil)
+		defer suite.resetMocks()
+
+		rr := suite.makeRequest("GET", "/swift-codes/"+strings.ToLower(swiftCode))
+
+		suite.Equal(http.StatusNotFound, rr.Code)
+		suite.Empty(rr.Header().Get(utils.HeaderContentLocation))
+	})
+}
+
+func (suite *RoutesTestSuite) TestGetBankDataByIban() {
+	for _, testCase := range GetBankDataByIbanTestCases {
+		suite.Run(testCase.Description, func() {
+			for _, nationalBankCode := range []string{"2490005", "249"} {
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.FindSwiftCodeByNationalBankCode),
+					mock.Anything,
+					"PL",
+					nationalBankCode,
+				).Return(testCase.NationalBankCodes[nationalBankCode], testCase.NegativeFindError).Maybe()
+			}
+			for _, bank := range testCase.StoredBanks {
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+					mock.Anything,
+					bank.SwiftCode,
+				).Return(&bank, nil)
+			}
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+				mock.Anything,
+				mock.Anything,
+			).Return(nil, nil).Maybe()
+			defer suite.resetMocks()
+
+			rr := suite.makeRequest("GET", "/iban/"+testCase.Iban+"/bic")
+
+			if testCase.ExpectedData != nil {
+				suite.assertJSONResponse(rr, testCase.ExpectedCode, testCase.ExpectedData)
+			} else {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+			}
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
 func (suite *RoutesTestSuite) TestGetBankDataByCountryCode() {
 	suite.Run("Positive Cases", func() {
 		for _, testCase := range GetBankDataByCountryCodePositiveTestCases {
@@ -138,10 +400,10 @@ func (suite *RoutesTestSuite) TestGetBankDataByCountryCode() {
 					utils.GetFunctionName(types.BankDataStore.FindBanksDataByCountryCode),
 					mock.Anything,
 					testCase.CountryCode,
-				).Return(testCase.ExpectedData.SwiftCodes, nil)
+				).Return(testCase.storeData(), nil)
 				defer suite.resetMocks()
 
-				rr := suite.makeRequest("GET", "/swift-codes/country/"+testCase.CountryCode)
+				rr := suite.makeRequest("GET", "/swift-codes/country/"+testCase.CountryCode+testCase.Query)
 
 				suite.Equal(testCase.ExpectedCode, rr.Code)
 
@@ -159,11 +421,15 @@ func (suite *RoutesTestSuite) TestGetBankDataByCountryCode() {
 					testCase.CountryCode,
 				).Return(testCase.NegativeFindValue, testCase.NegativeFindError).Maybe()
 				defer suite.resetMocks()
-				rr := suite.makeRequest("GET", "/swift-codes/country/"+testCase.CountryCode)
+				rr := suite.makeRequest("GET", "/swift-codes/country/"+testCase.CountryCode+testCase.Query)
 
 				suite.Equal(testCase.ExpectedCode, rr.Code)
 
-				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+				if testCase.ExpectedData != nil {
+					suite.assertJSONResponse(rr, testCase.ExpectedCode, &testCase.ExpectedData)
+				} else {
+					suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+				}
 
 				suite.store.AssertExpectations(suite.T())
 			})
@@ -171,20 +437,220 @@ func (suite *RoutesTestSuite) TestGetBankDataByCountryCode() {
 	})
 }
 
+func (suite *RoutesTestSuite) makeConditionalRequest(url, ifNoneMatch string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set(utils.HeaderIfNoneMatch, ifNoneMatch)
+	rr := httptest.NewRecorder()
+	suite.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func (suite *RoutesTestSuite) TestConditionalGetRequests() {
+	findDetails := utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode)
+	findBranches := utils.GetFunctionName(types.BankDataStore.FindBranchesDataByHqSwiftCode)
+	findByCountry := utils.GetFunctionName(types.BankDataStore.FindBanksDataByCountryCode)
+	changedBranches := branchTestBranches[:len(branchTestBranches)-1]
+
+	tests := []struct {
+		Description string
+		URL         string
+		MockStore   func(branches []types.BankDataCore)
+	}{
+		{
+			Description: "Headquarters with branches",
+			URL:         "/swift-codes/" + branchTestHq.SwiftCode,
+			MockStore: func(branches []types.BankDataCore) {
+				suite.store.On(findDetails, mock.Anything, branchTestHq.SwiftCode).Return(&branchTestHq, nil)
+				suite.store.On(findBranches, mock.Anything, branchTestHq.SwiftCode).Return(branches, nil)
+			},
+		},
+		{
+			Description: "Branch",
+			URL:         "/swift-codes/" + branchTestBank.SwiftCode,
+			MockStore: func(branches []types.BankDataCore) {
+				suite.store.On(findDetails, mock.Anything, branchTestBank.SwiftCode).Return(&branchTestBank, nil)
+				suite.store.On(findDetails, mock.Anything, branchTestHq.SwiftCode).Return(&branchTestHq, nil)
+				suite.store.On(findBranches, mock.Anything, branchTestHq.SwiftCode).Return(branches, nil)
+			},
+		},
+		{
+			Description: "Country",
+			URL:         "/swift-codes/country/PL",
+			MockStore: func(branches []types.BankDataCore) {
+				suite.store.On(findByCountry, mock.Anything, "PL").Return(append([]types.BankDataCore{branchTestHq.BankDataCore}, branches...), nil)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.Description, func() {
+			defer suite.resetMocks()
+			test.MockStore(branchTestBranches)
+			rr := suite.makeRequest("GET", test.URL)
+			suite.Equal(http.StatusOK, rr.Code)
+			etag := rr.Header().Get(utils.HeaderETag)
+			suite.NotEmpty(etag)
+
+			rr = suite.makeConditionalRequest(test.URL, etag)
+			suite.Equal(http.StatusNotModified, rr.Code)
+			suite.Equal(etag, rr.Header().Get(utils.HeaderETag))
+			suite.Empty(rr.Body.String())
+
+			suite.resetMocks()
+			test.MockStore(changedBranches)
+			rr = suite.makeConditionalRequest(test.URL, etag)
+			suite.Equal(http.StatusOK, rr.Code)
+			suite.NotEqual(etag, rr.Header().Get(utils.HeaderETag))
+			suite.NotEmpty(rr.Body.String())
+		})
+	}
+
+	suite.Run("Partial content has no ETag", func() {
+		defer suite.resetMocks()
+		suite.store.On(findByCountry, mock.Anything, "PL").Return(branchTestBranches, fmt.Errorf("connection lost"))
+
+		rr := suite.makeConditionalRequest("/swift-codes/country/PL", "*")
+
+		suite.Equal(http.StatusPartialContent, rr.Code)
+		suite.Empty(rr.Header().Get(utils.HeaderETag))
+	})
+}
+
+func (suite *RoutesTestSuite) TestGetCountries() {
+	for _, testCase := range GetCountriesTestCases {
+		suite.Run(testCase.Description, func() {
+			if testCase.ShouldCallStore {
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.FindCountriesStats),
+					mock.Anything,
+				).Return(testCase.StoreData, testCase.NegativeError)
+			}
+			defer suite.resetMocks()
+
+			rr := suite.makeRequest("GET", testCase.Path)
+
+			if testCase.ExpectedData != nil {
+				suite.assertJSONResponse(rr, testCase.ExpectedCode, testCase.ExpectedData)
+			} else {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+			}
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestGetBanksData() {
+	for _, testCase := range GetBanksDataTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBanksData),
+				mock.Anything,
+				testCase.Filter,
+			).Return(testCase.StoreData, testCase.NegativeFindError).Maybe()
+			defer suite.resetMocks()
+
+			rr := suite.makeRequest("GET", "/swift-codes"+testCase.Query)
+
+			if testCase.ExpectedData != nil {
+				suite.assertJSONResponse(rr, testCase.ExpectedCode, &testCase.ExpectedData)
+			} else {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+			}
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestGetBankGroup() {
+	for _, testCase := range GetBankGroupTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBanksData),
+				mock.Anything,
+				testCase.Filter,
+			).Return(testCase.StoreData, testCase.NegativeFindError).Maybe()
+			defer suite.resetMocks()
+
+			rr := suite.makeRequest("GET", "/banks/"+testCase.BankCode)
+
+			if testCase.ExpectedData != nil {
+				suite.assertJSONResponse(rr, testCase.ExpectedCode, testCase.ExpectedData)
+			} else {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+			}
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestSearchBanksData() {
+	for _, testCase := range SearchBanksDataTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.SearchBankData),
+				mock.Anything,
+				testCase.Terms,
+				testCase.Limit,
+			).Return(testCase.StoreData, testCase.NegativeSearchError).Maybe()
+			defer suite.resetMocks()
+
+			rr := suite.makeRequest("GET", "/search"+testCase.Query)
+
+			if testCase.ExpectedData != nil {
+				suite.assertJSONResponse(rr, testCase.ExpectedCode, &testCase.ExpectedData)
+			} else {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+			}
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestAutocompleteBanksData() {
+	for _, testCase := range AutocompleteBanksDataTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.AutocompleteBankData),
+				mock.Anything,
+				testCase.Prefix,
+				testCase.Limit,
+			).Return(testCase.StoreData, testCase.NegativeAutocompleteError).Maybe()
+			defer suite.resetMocks()
+
+			rr := suite.makeRequest("GET", "/autocomplete"+testCase.Query)
+
+			if testCase.ExpectedData != nil {
+				suite.assertJSONResponse(rr, testCase.ExpectedCode, &testCase.ExpectedData)
+			} else {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+			}
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestValidateSwiftCode() {
+	for _, testCase := range ValidateSwiftCodeTestCases {
+		suite.Run(testCase.Description, func() {
+			defer suite.resetMocks()
+
+			rr := suite.makeRequest("GET", "/swift-codes/"+testCase.SwiftCode+"/validate")
+
+			suite.assertJSONResponse(rr, testCase.ExpectedCode, testCase.ExpectedData)
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
 func (suite *RoutesTestSuite) TestPostBankData() {
 	suite.Run("Positive Cases", func() {
 		for _, testCase := range PostBankDataPositiveTestCases {
 			suite.Run(testCase.Description, func() {
 				suite.store.On(
-					utils.GetFunctionName(types.BankDataStore.DoesSwiftCodeExist),
-					mock.Anything,
-					testCase.BankData.SwiftCode,
-				).Return(int64(0), nil)
-				suite.store.On(
-					utils.GetFunctionName(types.BankDataStore.SaveBankData),
+					utils.GetFunctionName(types.BankDataStore.CreateBankData),
 					mock.Anything,
 					testCase.BankData,
-				).Return(nil)
+				).Return(true, nil)
 				defer suite.resetMocks()
 
 				rr := suite.makePostRequest("/swift-codes/", testCase.BankData)
@@ -200,15 +666,10 @@ func (suite *RoutesTestSuite) TestPostBankData() {
 		for _, testCase := range PostBankDataNegativeTestCases {
 			suite.Run(testCase.Description, func() {
 				suite.store.On(
-					utils.GetFunctionName(types.BankDataStore.DoesSwiftCodeExist),
-					mock.Anything,
-					testCase.BankData.SwiftCode,
-				).Return(testCase.NegativeExistValue, testCase.NegativeExistError).Maybe()
-				suite.store.On(
-					utils.GetFunctionName(types.BankDataStore.SaveBankData),
+					utils.GetFunctionName(types.BankDataStore.CreateBankData),
 					mock.Anything,
 					testCase.BankData,
-				).Return(testCase.NegativeSaveError).Maybe()
+				).Return(false, testCase.NegativeCreateError).Maybe()
 				defer suite.resetMocks()
 
 				rr := suite.makePostRequest("/swift-codes/", testCase.BankData)
@@ -223,12 +684,516 @@ func (suite *RoutesTestSuite) TestPostBankData() {
 	})
 }
 
+func (suite *RoutesTestSuite) TestPostBankDataBatch() {
+	for _, testCase := range PostBankDataBatchTestCases {
+		suite.Run(testCase.Description, func() {
+			for _, entry := range testCase.BankData {
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.CreateBankData),
+					mock.Anything,
+					entry,
+				).Return(!slices.Contains(testCase.Conflicts, entry.SwiftCode), testCase.NegativeCreateError).Maybe()
+			}
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.CreateBankDataBatch),
+				mock.Anything,
+				mock.Anything,
+			).Return(testCase.Conflicts, testCase.NegativeCreateError).Maybe()
+			defer suite.resetMocks()
+
+			body := []byte(testCase.RawBody)
+			if testCase.RawBody == "" {
+				body, _ = json.Marshal(testCase.BankData)
+			}
+			rr := suite.makeBodyRequest("POST", "/swift-codes/batch"+testCase.Query, body)
+
+			if testCase.ExpectedStatuses == nil {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.MessageIncludes)
+				return
+			}
+			suite.Equal(testCase.ExpectedCode, rr.Code)
+			var response types.BatchCreateResponse
+			suite.NoError(json.Unmarshal(rr.Body.Bytes(), &response))
+			statuses := make([]string, len(response.Results))
+			for i, result := range response.Results {
+				statuses[i] = result.Status
+			}
+			suite.Equal(testCase.ExpectedStatuses, statuses)
+			for _, result := range response.Results {
+				if result.Status == utils.BatchStatusInvalid {
+					suite.Equal(utils.ProblemCountryMismatch, result.Code)
+				}
+			}
+			suite.Equal(testCase.ExpectedCreated, response.Created)
+		})
+	}
+}
+
+func (suite *RoutesTestSuite) TestLookupBanksData() {
+	for _, testCase := range LookupBanksDataTestCases {
+		suite.Run(testCase.Description, func() {
+			suite.store.On(
+				utils.GetFunctionName(types.BankDataStore.FindBanksDetailsBySwiftCodes),
+				mock.Anything,
+				testCase.StoreSwiftCodes,
+			).Return(testCase.StoreData, testCase.NegativeFindError).Maybe()
+			defer suite.resetMocks()
+
+			rr := suite.makeBodyRequest("POST", "/swift-codes/lookup", []byte(testCase.Body))
+
+			if testCase.ExpectedData != nil {
+				suite.assertJSONResponse(rr, testCase.ExpectedCode, &testCase.ExpectedData)
+			} else {
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.ErrorIncludes)
+			}
+			suite.store.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func TestPostBankDataConcurrently(t *testing.T) {
+	router := mux.NewRouter()
+	swiftCode.NewSwiftCodeHandler(store.NewMemoryStore(), store.NewMemoryIdempotencyStore(time.Hour)).RegisterRoutes(router)
+	bankData := PostBankDataPositiveTestCases[0].BankData
+	body, _ := json.Marshal(bankData)
+
+	const requests = 50
+	codes := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("POST", "/swift-codes", bytes.NewReader(body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			codes <- rr.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	statuses := make(map[int]int)
+	for code := range codes {
+		statuses[code]++
+	}
+	assert.Equal(t, map[int]int{http.StatusCreated: 1, http.StatusConflict: requests - 1}, statuses)
+}
+
+func (suite *RoutesTestSuite) makeIdempotentRequest(method, url, key string, body []byte) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+	req.Header.Set(utils.HeaderContentType, utils.ContentTypeJson)
+	req.Header.Set(utils.HeaderIdempotencyKey, key)
+	rr := httptest.NewRecorder()
+	suite.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func (suite *RoutesTestSuite) TestIdempotentRequests() {
+	bankData := PostBankDataPositiveTestCases[0].BankData
+	body, _ := json.Marshal(bankData)
+	createBankData := utils.GetFunctionName(types.BankDataStore.CreateBankData)
+
+	suite.Run("Retried POST replays the stored response", func() {
+		suite.store.On(createBankData, mock.Anything, bankData).Return(true, nil).Once()
+		defer suite.resetMocks()
+
+		first := suite.makeIdempotentRequest("POST", "/swift-codes", "post-retry", body)
+		retry := suite.makeIdempotentRequest("POST", "/swift-codes", "post-retry", body)
+
+		suite.Equal(http.StatusCreated, first.Code)
+		suite.Empty(first.Header().Get(utils.HeaderIdempotentReplay))
+		suite.Equal(first.Code, retry.Code)
+		suite.Equal(first.Header().Get(utils.HeaderContentType), retry.Header().Get(utils.HeaderContentType))
+		suite.Equal(first.Body.String(), retry.Body.String())
+		suite.Equal("true", retry.Header().Get(utils.HeaderIdempotentReplay))
+		suite.store.AssertNumberOfCalls(suite.T(), createBankData, 1)
+	})
+
+	suite.Run("Retried DELETE replays the stored response", func() {
+		suite.store.On(utils.GetFunctionName(types.BankDataStore.DoesSwiftCodeExist), mock.Anything, bankData.SwiftCode).Return(int64(1), nil).Once()
+		suite.store.On(utils.GetFunctionName(types.BankDataStore.DeleteBankData), mock.Anything, bankData.SwiftCode, int64(0)).Return(nil).Once()
+		defer suite.resetMocks()
+
+		first := suite.makeIdempotentRequest("DELETE", "/swift-codes/"+bankData.SwiftCode, "delete-retry", nil)
+		retry := suite.makeIdempotentRequest("DELETE", "/swift-codes/"+bankData.SwiftCode, "delete-retry", nil)
+
+		suite.assertMessageResponse(first, http.StatusOK, "bank data succesfully deleted")
+		suite.assertMessageResponse(retry, http.StatusOK, "bank data succesfully deleted")
+		suite.Equal("/swift-codes/"+bankData.SwiftCode, retry.Header().Get(utils.HeaderContentLocation))
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Key reused for a different request", func() {
+		suite.store.On(createBankData, mock.Anything, bankData).Return(true, nil).Once()
+		defer suite.resetMocks()
+		changed := bankData
+		changed.Address = "Changed Street 1"
+		changedBody, _ := json.Marshal(changed)
+
+		suite.Equal(http.StatusCreated, suite.makeIdempotentRequest("POST", "/swift-codes", "post-reused", body).Code)
+
+		suite.assertProblemResponse(suite.makeIdempotentRequest("POST", "/swift-codes", "post-reused", changedBody), http.StatusUnprocessableEntity, utils.ProblemIdemKeyReused)
+		suite.assertProblemResponse(suite.makeIdempotentRequest("POST", "/swift-codes/", "post-reused", body), http.StatusUnprocessableEntity, utils.ProblemIdemKeyReused)
+		suite.store.AssertNumberOfCalls(suite.T(), createBankData, 1)
+	})
+
+	suite.Run("Retry while the request is still handled", func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		suite.store.On(createBankData, mock.Anything, bankData).Run(func(mock.Arguments) {
+			close(started)
+			<-release
+		}).Return(true, nil).Once()
+		defer suite.resetMocks()
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() {
+			done <- suite.makeIdempotentRequest("POST", "/swift-codes", "post-in-progress", body)
+		}()
+		<-started
+		suite.assertProblemResponse(suite.makeIdempotentRequest("POST", "/swift-codes", "post-in-progress", body), http.StatusConflict, utils.ProblemIdemKeyInUse)
+		close(release)
+
+		suite.Equal(http.StatusCreated, (<-done).Code)
+	})
+
+	suite.Run("Server errors are not stored", func() {
+		suite.store.On(createBankData, mock.Anything, bankData).Return(false, fmt.Errorf("connection refused")).Once()
+		suite.store.On(createBankData, mock.Anything, bankData).Return(true, nil).Once()
+		defer suite.resetMocks()
+
+		suite.assertProblemResponse(suite.makeIdempotentRequest("POST", "/swift-codes", "post-failed", body), http.StatusInternalServerError, utils.ProblemStoreUnavailable)
+		retry := suite.makeIdempotentRequest("POST", "/swift-codes", "post-failed", body)
+
+		suite.Equal(http.StatusCreated, retry.Code)
+		suite.Empty(retry.Header().Get(utils.HeaderIdempotentReplay))
+	})
+
+	suite.Run("Too long key", func() {
+		defer suite.resetMocks()
+
+		rr := suite.makeIdempotentRequest("POST", "/swift-codes", strings.Repeat("k", utils.IdempotencyKeyMaxLength+1), body)
+
+		suite.assertProblemResponse(rr, http.StatusBadRequest, utils.ProblemInvalidIdemKey)
+		suite.store.AssertNotCalled(suite.T(), createBankData, mock.Anything, mock.Anything)
+	})
+}
+
+func (suite *RoutesTestSuite) makeIfMatchRequest(method, url, ifMatch string, body []byte) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+	req.Header.Set(utils.HeaderContentType, utils.ContentTypeJson)
+	req.Header.Set(utils.HeaderIfMatch, ifMatch)
+	rr := httptest.NewRecorder()
+	suite.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func (suite *RoutesTestSuite) TestConditionalWriteRequests() {
+	updateBankData := utils.GetFunctionName(types.BankDataStore.UpdateBankData)
+	deleteBankData := utils.GetFunctionName(types.BankDataStore.DeleteBankData)
+	findDetails := utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode)
+	findBranches := utils.GetFunctionName(types.BankDataStore.FindBranchesDataByHqSwiftCode)
+	putData := PutBankDataPositiveTestCases[0].BankData
+	putBody, _ := json.Marshal(putData)
+	putStored := putData
+	putStored.Version = 3
+	patchCase := PatchBankDataPositiveTestCases[0]
+	stored := *patchCase.ExistingData
+	stored.Version = 5
+	patched := patchCase.ExpectedData
+	patched.Version = 5
+
+	suite.Run("PUT at the current version", func() {
+		suite.store.On(findDetails, mock.Anything, putData.SwiftCode).Return(&putStored, nil).Once()
+		suite.store.On(updateBankData, mock.Anything, putData, int64(3)).Return(true, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("PUT", "/swift-codes/"+putData.SwiftCode, `"3"`, putBody)
+
+		suite.assertMessageResponse(rr, http.StatusOK, "bank data succesfully updated")
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("PUT with one of the entity tags at the current version", func() {
+		suite.store.On(findDetails, mock.Anything, putData.SwiftCode).Return(&putStored, nil).Once()
+		suite.store.On(updateBankData, mock.Anything, putData, int64(3)).Return(true, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("PUT", "/swift-codes/"+putData.SwiftCode, `W/"3", "2-9f86d0", "3-9f86d0"`, putBody)
+
+		suite.assertMessageResponse(rr, http.StatusOK, "bank data succesfully updated")
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("PUT at a stale version", func() {
+		suite.store.On(findDetails, mock.Anything, putData.SwiftCode).Return(&putStored, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("PUT", "/swift-codes/"+putData.SwiftCode, `"2"`, putBody)
+
+		problem := suite.assertProblemResponse(rr, http.StatusPreconditionFailed, utils.ProblemPrecondition)
+		suite.Contains(problem.Detail, "is at version 3")
+		suite.store.AssertNotCalled(suite.T(), updateBankData, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	suite.Run("PUT changed after the version check", func() {
+		suite.store.On(findDetails, mock.Anything, putData.SwiftCode).Return(&putStored, nil).Once()
+		suite.store.On(updateBankData, mock.Anything, putData, int64(3)).
+			Return(false, fmt.Errorf("failed to update: %w", utils.VersionMismatchError{SwiftCode: putData.SwiftCode, ExpectedVersion: 3})).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("PUT", "/swift-codes/"+putData.SwiftCode, `"3"`, putBody)
+
+		problem := suite.assertProblemResponse(rr, http.StatusPreconditionFailed, utils.ProblemPrecondition)
+		suite.Contains(problem.Detail, "is no longer at version 3")
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Weak entity tag never matches", func() {
+		suite.store.On(findDetails, mock.Anything, putData.SwiftCode).Return(&putStored, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("PUT", "/swift-codes/"+putData.SwiftCode, `W/"3"`, putBody)
+
+		suite.assertProblemResponse(rr, http.StatusPreconditionFailed, utils.ProblemPrecondition)
+		suite.store.AssertNotCalled(suite.T(), updateBankData, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	suite.Run("Invalid If-Match", func() {
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("PUT", "/swift-codes/"+putData.SwiftCode, "3", putBody)
+
+		suite.assertProblemResponse(rr, http.StatusBadRequest, utils.ProblemInvalidIfMatch)
+		suite.store.AssertNotCalled(suite.T(), updateBankData, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	suite.Run("PATCH with the ETag of a read", func() {
+		suite.store.On(findDetails, mock.Anything, mock.Anything).Return(&stored, nil)
+		suite.store.On(findBranches, mock.Anything, mock.Anything).Return([]types.BankDataCore{}, nil)
+		suite.store.On(updateBankData, mock.Anything, patched, int64(5)).Return(true, nil).Once()
+		defer suite.resetMocks()
+		etag := suite.makeRequest("GET", "/swift-codes/"+patchCase.SwiftCode).Header().Get(utils.HeaderETag)
+		suite.True(strings.HasPrefix(etag, `"5-`))
+
+		rr := suite.makeIfMatchRequest("PATCH", "/swift-codes/"+patchCase.SwiftCode, etag, []byte(patchCase.Patch))
+
+		suite.assertMessageResponse(rr, http.StatusOK, "bank data succesfully updated")
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("PATCH is conditional on the fetched version", func() {
+		suite.store.On(findDetails, mock.Anything, patchCase.SwiftCode).Return(&stored, nil).Once()
+		suite.store.On(updateBankData, mock.Anything, patched, int64(5)).Return(true, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeBodyRequest("PATCH", "/swift-codes/"+patchCase.SwiftCode, []byte(patchCase.Patch))
+
+		suite.assertMessageResponse(rr, http.StatusOK, "bank data succesfully updated")
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Unconditional PATCH racing a concurrent write is applied again", func() {
+		changed := stored
+		changed.Version = 6
+		patchedChanged := patched
+		patchedChanged.Version = 6
+		suite.store.On(findDetails, mock.Anything, patchCase.SwiftCode).Return(&stored, nil).Once()
+		suite.store.On(findDetails, mock.Anything, patchCase.SwiftCode).Return(&changed, nil).Once()
+		suite.store.On(updateBankData, mock.Anything, patched, int64(5)).
+			Return(false, utils.VersionMismatchError{SwiftCode: patchCase.SwiftCode, ExpectedVersion: 5}).Once()
+		suite.store.On(updateBankData, mock.Anything, patchedChanged, int64(6)).Return(true, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeBodyRequest("PATCH", "/swift-codes/"+patchCase.SwiftCode, []byte(patchCase.Patch))
+
+		suite.assertMessageResponse(rr, http.StatusOK, "bank data succesfully updated")
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Unconditional PATCH that keeps racing concurrent writes", func() {
+		suite.store.On(findDetails, mock.Anything, patchCase.SwiftCode).Return(&stored, nil).Times(utils.PatchMaxAttempts)
+		suite.store.On(updateBankData, mock.Anything, patched, int64(5)).
+			Return(false, utils.VersionMismatchError{SwiftCode: patchCase.SwiftCode, ExpectedVersion: 5}).Times(utils.PatchMaxAttempts)
+		defer suite.resetMocks()
+
+		rr := suite.makeBodyRequest("PATCH", "/swift-codes/"+patchCase.SwiftCode, []byte(patchCase.Patch))
+
+		suite.assertProblemResponse(rr, http.StatusConflict, utils.ProblemEditConflict)
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Conditional PATCH racing a concurrent write", func() {
+		suite.store.On(findDetails, mock.Anything, patchCase.SwiftCode).Return(&stored, nil).Once()
+		suite.store.On(updateBankData, mock.Anything, patched, int64(5)).
+			Return(false, utils.VersionMismatchError{SwiftCode: patchCase.SwiftCode, ExpectedVersion: 5}).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("PATCH", "/swift-codes/"+patchCase.SwiftCode, `"5"`, []byte(patchCase.Patch))
+
+		suite.assertProblemResponse(rr, http.StatusPreconditionFailed, utils.ProblemPrecondition)
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("PATCH at a stale version", func() {
+		suite.store.On(findDetails, mock.Anything, patchCase.SwiftCode).Return(&stored, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("PATCH", "/swift-codes/"+patchCase.SwiftCode, `"4"`, []byte(patchCase.Patch))
+
+		suite.assertProblemResponse(rr, http.StatusPreconditionFailed, utils.ProblemPrecondition)
+		suite.store.AssertNotCalled(suite.T(), updateBankData, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	suite.Run("DELETE at the current version", func() {
+		suite.store.On(findDetails, mock.Anything, putData.SwiftCode).Return(&putStored, nil).Once()
+		suite.store.On(deleteBankData, mock.Anything, putData.SwiftCode, int64(3)).Return(nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("DELETE", "/swift-codes/"+putData.SwiftCode, `"3"`, nil)
+
+		suite.assertMessageResponse(rr, http.StatusOK, "bank data succesfully deleted")
+		suite.store.AssertExpectations(suite.T())
+	})
+
+	suite.Run("DELETE at a stale version", func() {
+		suite.store.On(findDetails, mock.Anything, putData.SwiftCode).Return(&putStored, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("DELETE", "/swift-codes/"+putData.SwiftCode, `"2"`, nil)
+
+		suite.assertProblemResponse(rr, http.StatusPreconditionFailed, utils.ProblemPrecondition)
+		suite.store.AssertNotCalled(suite.T(), deleteBankData, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	suite.Run("DELETE of a missing SWIFT code", func() {
+		suite.store.On(findDetails, mock.Anything, putData.SwiftCode).Return(nil, nil).Once()
+		defer suite.resetMocks()
+
+		rr := suite.makeIfMatchRequest("DELETE", "/swift-codes/"+putData.SwiftCode, `"3"`, nil)
+
+		suite.assertProblemResponse(rr, http.StatusNotFound, utils.ProblemNotFound)
+		suite.store.AssertNotCalled(suite.T(), deleteBankData, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func (suite *RoutesTestSuite) TestPutBankData() {
+	suite.Run("Positive Cases", func() {
+		for _, testCase := range PutBankDataPositiveTestCases {
+			suite.Run(testCase.Description, func() {
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.UpdateBankData),
+					mock.Anything,
+					testCase.BankData,
+					int64(0),
+				).Return(true, nil)
+				defer suite.resetMocks()
+
+				body, _ := json.Marshal(testCase.BankData)
+				rr := suite.makeBodyRequest("PUT", "/swift-codes/"+testCase.SwiftCode, body)
+
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.MessageIncludes)
+				suite.store.AssertExpectations(suite.T())
+			})
+		}
+	})
+	suite.Run("Negative Cases", func() {
+		for _, testCase := range PutBankDataNegativeTestCases {
+			suite.Run(testCase.Description, func() {
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.UpdateBankData),
+					mock.Anything,
+					testCase.BankData,
+					int64(0),
+				).Return(false, testCase.NegativeUpdateError).Maybe()
+				defer suite.resetMocks()
+
+				body, _ := json.Marshal(testCase.BankData)
+				rr := suite.makeBodyRequest("PUT", "/swift-codes/"+testCase.SwiftCode, body)
+
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.MessageIncludes)
+				suite.store.AssertExpectations(suite.T())
+			})
+		}
+	})
+}
+
+func (suite *RoutesTestSuite) TestPutBankDataWithNormalizedSwiftCode() {
+	testCase := PutBankDataPositiveTestCases[0]
+	suite.store.On(
+		utils.GetFunctionName(types.BankDataStore.UpdateBankData),
+		mock.Anything,
+		testCase.BankData,
+		int64(0),
+	).Return(true, nil)
+	defer suite.resetMocks()
+
+	bankData := testCase.BankData
+	bankData.SwiftCode = " " + strings.ToLower(testCase.SwiftCode[:utils.SwiftCodeLength])
+	body, _ := json.Marshal(bankData)
+	rr := suite.makeBodyRequest("PUT", "/swift-codes/"+testCase.SwiftCode[:utils.SwiftCodeLength], body)
+
+	suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.MessageIncludes)
+	suite.Equal("/swift-codes/"+testCase.SwiftCode, rr.Header().Get(utils.HeaderContentLocation))
+	suite.store.AssertExpectations(suite.T())
+}
+
+func (suite *RoutesTestSuite) TestPatchBankData() {
+	suite.Run("Positive Cases", func() {
+		for _, testCase := range PatchBankDataPositiveTestCases {
+			suite.Run(testCase.Description, func() {
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+					mock.Anything,
+					testCase.SwiftCode,
+				).Return(testCase.ExistingData, nil)
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.UpdateBankData),
+					mock.Anything,
+					testCase.ExpectedData,
+					testCase.ExistingData.Version,
+				).Return(true, nil)
+				defer suite.resetMocks()
+
+				rr := suite.makeBodyRequest("PATCH", "/swift-codes/"+testCase.SwiftCode, []byte(testCase.Patch))
+
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.MessageIncludes)
+				suite.store.AssertExpectations(suite.T())
+			})
+		}
+	})
+	suite.Run("Negative Cases", func() {
+		for _, testCase := range PatchBankDataNegativeTestCases {
+			suite.Run(testCase.Description, func() {
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.FindBankDetailsBySwiftCode),
+					mock.Anything,
+					testCase.SwiftCode,
+				).Return(testCase.ExistingData, testCase.NegativeFindError).Maybe()
+				suite.store.On(
+					utils.GetFunctionName(types.BankDataStore.UpdateBankData),
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+				).Return(false, testCase.NegativeUpdateError).Maybe()
+				defer suite.resetMocks()
+
+				rr := suite.makeBodyRequest("PATCH", "/swift-codes/"+testCase.SwiftCode, []byte(testCase.Patch))
+
+				suite.assertMessageResponse(rr, testCase.ExpectedCode, testCase.MessageIncludes)
+				suite.store.AssertExpectations(suite.T())
+			})
+		}
+	})
+}
+
 func (suite *RoutesTestSuite) TestDeleteBankData() {
 	suite.Run("Positive Cases", func() {
 		for _, testCase := range DeleteBankDataPositiveTestCases {
 			suite.Run(testCase.Description, func() {
 				suite.store.On(utils.GetFunctionName(types.BankDataStore.DoesSwiftCodeExist), mock.Anything, testCase.SwiftCode).Return(int64(1), nil)
-				suite.store.On(utils.GetFunctionName(types.BankDataStore.DeleteBankData), mock.Anything, testCase.SwiftCode).Return(nil)
+				suite.store.On(utils.GetFunctionName(types.BankDataStore.DeleteBankData), mock.Anything, testCase.SwiftCode, int64(0)).Return(nil)
 				defer suite.resetMocks()
 
 				rr := suite.makeRequest("DELETE", "/swift-codes/"+testCase.SwiftCode)
@@ -252,6 +1217,7 @@ func (suite *RoutesTestSuite) TestDeleteBankData() {
 					utils.GetFunctionName(types.BankDataStore.DeleteBankData),
 					mock.Anything,
 					testCase.SwiftCode,
+					int64(0),
 				).Return(testCase.NegativeDeleteError).Maybe()
 				defer suite.resetMocks()
 
@@ -290,12 +1256,61 @@ func (m *mockSwiftCodeStore) FindBanksDataByCountryCode(ctx context.Context, cou
 	args := m.Called(ctx, countryCode)
 	return args.Get(0).([]types.BankDataCore), args.Error(1)
 }
+func (m *mockSwiftCodeStore) FindBanksData(ctx context.Context, filter types.BankDataFilter) ([]types.BankDataCore, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]types.BankDataCore), args.Error(1)
+}
+func (m *mockSwiftCodeStore) SearchBankData(ctx context.Context, terms []string, limit int) ([]types.SearchResult, error) {
+	args := m.Called(ctx, terms, limit)
+	return args.Get(0).([]types.SearchResult), args.Error(1)
+}
+func (m *mockSwiftCodeStore) AutocompleteBankData(ctx context.Context, prefix string, limit int) ([]types.BankDataCore, error) {
+	args := m.Called(ctx, prefix, limit)
+	return args.Get(0).([]types.BankDataCore), args.Error(1)
+}
+func (m *mockSwiftCodeStore) FindBanksDetailsBySwiftCodes(ctx context.Context, swiftCodes []string) ([]types.BankDataDetails, error) {
+	args := m.Called(ctx, swiftCodes)
+	return args.Get(0).([]types.BankDataDetails), args.Error(1)
+}
+func (m *mockSwiftCodeStore) FindCountriesStats(ctx context.Context) ([]types.CountryStats, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]types.CountryStats), args.Error(1)
+}
+func (m *mockSwiftCodeStore) ExportBankData(ctx context.Context, filter types.ExportFilter, fn func(types.BankDataRecord) error) error {
+	args := m.Called(ctx, filter)
+	for _, record := range args.Get(0).([]types.BankDataRecord) {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+func (m *mockSwiftCodeStore) SaveNationalBankCode(ctx context.Context, code types.NationalBankCode) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+func (m *mockSwiftCodeStore) FindSwiftCodeByNationalBankCode(ctx context.Context, countryIso2 string, nationalBankCode string) (string, error) {
+	args := m.Called(ctx, countryIso2, nationalBankCode)
+	return args.String(0), args.Error(1)
+}
 func (m *mockSwiftCodeStore) SaveBankData(ctx context.Context, data types.BankDataDetails) error {
 	args := m.Called(ctx, data)
 	return args.Error(0)
 }
-func (m *mockSwiftCodeStore) DeleteBankData(ctx context.Context, swiftCode string) error {
-	args := m.Called(ctx, swiftCode)
+func (m *mockSwiftCodeStore) CreateBankData(ctx context.Context, data types.BankDataDetails) (bool, error) {
+	args := m.Called(ctx, data)
+	return args.Bool(0), args.Error(1)
+}
+func (m *mockSwiftCodeStore) CreateBankDataBatch(ctx context.Context, data []types.BankDataDetails) ([]string, error) {
+	args := m.Called(ctx, data)
+	return args.Get(0).([]string), args.Error(1)
+}
+func (m *mockSwiftCodeStore) UpdateBankData(ctx context.Context, data types.BankDataDetails, expectedVersion int64) (bool, error) {
+	args := m.Called(ctx, data, expectedVersion)
+	return args.Bool(0), args.Error(1)
+}
+func (m *mockSwiftCodeStore) DeleteBankData(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	args := m.Called(ctx, swiftCode, expectedVersion)
 	return args.Error(0)
 }
 func (m *mockSwiftCodeStore) DoesSwiftCodeExist(ctx context.Context, swiftCode string) (int64, error) {
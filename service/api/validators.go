@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
+	"github.com/jbub/banking/iban"
+	"github.com/jbub/banking/swift"
 )
 
 func ValidateInput(input interface{}, tags string) error {
@@ -24,7 +29,10 @@ func ValidateInput(input interface{}, tags string) error {
 	if err == nil {
 		return nil
 	}
+	return newValidationError(err)
+}
 
+func newValidationError(err error) utils.ValidationError {
 	errors := make(map[string]string)
 	if validationErrs, ok := err.(validator.ValidationErrors); ok {
 		for _, fieldErr := range validationErrs {
@@ -36,35 +44,268 @@ func ValidateInput(input interface{}, tags string) error {
 	return utils.ValidationError{Errors: errors}
 }
 
+// validatePathParam validates the path parameter against the tags, reporting the failure under the parameter name.
+func validatePathParam(name string, value string, tags string, code string) error {
+	message := validationMessage(value, tags)
+	if message == "" {
+		return nil
+	}
+	return utils.ProblemError{Code: code, Err: utils.ValidationError{Errors: map[string]string{name: message}}}
+}
+
+// NormalizeSwiftCodePath replaces the SWIFT code path parameter with its canonical form, so that e.g. a lowercase
+// BIC8 finds the bank data stored under the BIC11. The returned path points to the canonical resource.
+func NormalizeSwiftCodePath(r *http.Request) (*http.Request, string) {
+	vars := mux.Vars(r)
+	swiftCode := vars[utils.PathParamSwiftCode]
+	normalized := utils.NormalizeSwiftCode(swiftCode)
+	path := strings.TrimSuffix(r.URL.Path, swiftCode) + normalized
+	if normalized == swiftCode {
+		return r, path
+	}
+
+	normalizedVars := make(map[string]string, len(vars))
+	for key, value := range vars {
+		normalizedVars[key] = value
+	}
+	normalizedVars[utils.PathParamSwiftCode] = normalized
+	return mux.SetURLVars(r, normalizedVars), path
+}
+
 func ValidateSwiftCode(r *http.Request) error {
 	swiftCode := mux.Vars(r)[utils.PathParamSwiftCode]
-	return ValidateInput(swiftCode, "required,"+utils.ValidatorSwiftCode)
+	return validatePathParam(utils.PathParamSwiftCode, swiftCode, "required,"+utils.ValidatorSwiftCode, utils.ProblemInvalidSwiftCode)
+}
+
+// DecodeSwiftCode splits the SWIFT code into its parts. BIC8 codes are decoded as the equivalent BIC11 of the primary office,
+// and the second character of the location code tells test (0) and passive (1) participants apart.
+func DecodeSwiftCode(swiftCode string) types.SwiftCodeStructure {
+	structure := types.SwiftCodeStructure{SwiftCode: swiftCode}
+	parsed, err := swift.Parse(utils.CleanSwiftCode(swiftCode))
+	if err != nil {
+		structure.Error = err.Error()
+		return structure
+	}
+
+	structure.Valid = true
+	structure.Format = utils.SwiftFormatBic11
+	if parsed.Type() == swift.Type8 {
+		structure.Format = utils.SwiftFormatBic8
+	}
+	structure.Normalized = utils.NormalizeSwiftCode(swiftCode)
+	structure.BankCode = parsed.BankCode()
+	structure.CountryIso2 = parsed.CountryCode()
+	structure.CountryName = utils.GetCountryNameFromCountryCode(structure.CountryIso2)
+	structure.LocationCode = parsed.LocationCode()
+	structure.BranchCode = structure.Normalized[utils.SwiftCodeLength:]
+	structure.IsHeadquarter = structure.BranchCode == utils.BranchSuffix
+	structure.IsTestCode = structure.LocationCode[1] == utils.SwiftLocationTest
+	structure.IsPassive = structure.LocationCode[1] == utils.SwiftLocationPassive
+	return structure
+}
+
+func ValidateBankCode(r *http.Request) error {
+	bankCode := strings.ToUpper(mux.Vars(r)[utils.PathParamBankCode])
+	return validatePathParam(utils.PathParamBankCode, bankCode, fmt.Sprintf("required,len=%d,alpha", utils.BankCodeLength), utils.ProblemInvalidBankCode)
+}
+
+func ValidateIban(r *http.Request) error {
+	if err := iban.Validate(utils.CleanIban(mux.Vars(r)[utils.PathParamIban])); err != nil {
+		return utils.ProblemError{Code: utils.ProblemInvalidIban, Err: fmt.Errorf("invalid IBAN: %w", err)}
+	}
+	return nil
 }
 
 func ValidateCountryCode(r *http.Request) error {
 	countryCode := mux.Vars(r)[utils.PathParamCountryIso2]
-	return ValidateInput(countryCode, "required,"+utils.ValidatorCountryIso2)
+	return validatePathParam(utils.PathParamCountryIso2, countryCode, "required,"+utils.ValidatorCountryIso2, utils.ProblemInvalidCountry)
+}
+
+func ParseBankDataFilter(query url.Values) (types.BankDataFilter, error) {
+	filter := types.BankDataFilter{
+		CountryIso2: strings.ToUpper(query.Get(utils.QueryParamCountry)),
+		BankCode:    strings.ToUpper(query.Get(utils.QueryParamBankCode)),
+		BankName:    strings.TrimSpace(query.Get(utils.QueryParamBankName)),
+	}
+	isHeadquarter := query.Get(utils.QueryParamIsHq)
+
+	errors := make(map[string]string)
+	validateQueryParam(errors, utils.QueryParamCountry, filter.CountryIso2, utils.ValidatorCountryIso2)
+	validateQueryParam(errors, utils.QueryParamBankCode, filter.BankCode, fmt.Sprintf("len=%d,alpha", utils.BankCodeLength))
+	validateQueryParam(errors, utils.QueryParamBankName, filter.BankName, fmt.Sprintf("max=%d", utils.BankNameMaxLength))
+	validateQueryParam(errors, utils.QueryParamIsHq, isHeadquarter, "boolean")
+	if len(errors) > 0 {
+		return filter, utils.ValidationError{Errors: errors}
+	}
+	if filter.CountryIso2 == "" && filter.BankCode == "" {
+		return filter, fmt.Errorf("at least one of the %s and %s query parameters is required", utils.QueryParamCountry, utils.QueryParamBankCode)
+	}
+	if isHeadquarter != "" {
+		value, _ := strconv.ParseBool(isHeadquarter)
+		filter.IsHeadquarter = &value
+	}
+	return filter, nil
+}
+
+// ParseExportQuery reads the optional country and RFC 3339 modifiedSince filters, and the ndjson or csv format,
+// which defaults to ndjson.
+func ParseExportQuery(query url.Values) (types.ExportQuery, error) {
+	export := types.ExportQuery{
+		Filter: types.ExportFilter{CountryIso2: strings.ToUpper(query.Get(utils.QueryParamCountry))},
+		Format: strings.ToLower(query.Get(utils.QueryParamFormat)),
+	}
+	modifiedSince := query.Get(utils.QueryParamModifiedSince)
+
+	errors := make(map[string]string)
+	validateQueryParam(errors, utils.QueryParamCountry, export.Filter.CountryIso2, utils.ValidatorCountryIso2)
+	validateQueryParam(errors, utils.QueryParamFormat, export.Format, fmt.Sprintf("oneof=%s %s", utils.ExportFormatNdjson, utils.ExportFormatCsv))
+	validateQueryParam(errors, utils.QueryParamModifiedSince, modifiedSince, "datetime="+time.RFC3339)
+	if len(errors) > 0 {
+		return export, utils.ValidationError{Errors: errors}
+	}
+	if export.Format == "" {
+		export.Format = utils.ExportFormatNdjson
+	}
+	if modifiedSince != "" {
+		parsed, _ := time.Parse(time.RFC3339, modifiedSince)
+		export.Filter.ModifiedSince = parsed.UTC()
+	}
+	return export, nil
+}
+
+// ParseIfMatch reads the bank data versions from the entity tags of the If-Match header - the ETags of the single record reads,
+// e.g. "3-9f86d0...", or bare versions, e.g. "3". If-Match compares entity tags strongly, so weak tags and tags without
+// a version never match.
+func ParseIfMatch(ifMatch string) (types.VersionCondition, error) {
+	if strings.TrimSpace(ifMatch) == "" {
+		return types.VersionCondition{}, nil
+	}
+	tags, anyTag, err := parseEntityTags(ifMatch)
+	if err != nil {
+		return types.VersionCondition{}, fmt.Errorf("invalid %s header: %w", utils.HeaderIfMatch, err)
+	}
+	if anyTag {
+		return types.VersionCondition{}, nil
+	}
+	condition := types.VersionCondition{Conditional: true}
+	for _, tag := range tags {
+		if tag.weak {
+			continue
+		}
+		versionPart, _, _ := strings.Cut(tag.opaque, utils.ETagVersionSeparator)
+		if version, err := strconv.ParseInt(versionPart, 10, 64); err == nil && version > 0 {
+			condition.Versions = append(condition.Versions, version)
+		}
+	}
+	return condition, nil
+}
+
+func ParseSearchQuery(query url.Values) (types.SearchQuery, error) {
+	search := types.SearchQuery{
+		Query: strings.TrimSpace(query.Get(utils.QueryParamSearch)),
+		Limit: utils.SearchDefaultLimit,
+	}
+	if search.Query == "" {
+		return search, fmt.Errorf("the %s query parameter is required", utils.QueryParamSearch)
+	}
+	if len(search.Query) > utils.SearchMaxQueryLength {
+		return search, fmt.Errorf("the %s query parameter can have at most %d characters", utils.QueryParamSearch, utils.SearchMaxQueryLength)
+	}
+	if value := query.Get(utils.QueryParamLimit); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > utils.SearchMaxLimit {
+			return search, fmt.Errorf("%s has to be a number between 1 and %d", utils.QueryParamLimit, utils.SearchMaxLimit)
+		}
+		search.Limit = limit
+	}
+	search.Terms = utils.SearchTokens(search.Query)
+	if len(search.Terms) == 0 {
+		return search, fmt.Errorf("the %s query parameter has to contain a word of at least %d letters or digits", utils.QueryParamSearch, utils.SearchMinTokenLength)
+	}
+	return search, nil
+}
+
+func ParseAutocompleteQuery(query url.Values) (types.AutocompleteQuery, error) {
+	prefix := query.Get(utils.QueryParamPrefix)
+	autocomplete := types.AutocompleteQuery{
+		Prefix: utils.FoldSearchText(prefix),
+		Limit:  utils.AutocompleteDefaultLimit,
+	}
+	if len(prefix) > utils.AutocompleteMaxPrefixLength {
+		return autocomplete, fmt.Errorf("the %s query parameter can have at most %d characters", utils.QueryParamPrefix, utils.AutocompleteMaxPrefixLength)
+	}
+	if autocomplete.Prefix == "" {
+		return autocomplete, fmt.Errorf("the %s query parameter has to contain a letter or a digit", utils.QueryParamPrefix)
+	}
+	if value := query.Get(utils.QueryParamLimit); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > utils.AutocompleteMaxLimit {
+			return autocomplete, fmt.Errorf("%s has to be a number between 1 and %d", utils.QueryParamLimit, utils.AutocompleteMaxLimit)
+		}
+		autocomplete.Limit = limit
+	}
+	return autocomplete, nil
+}
+
+func validateQueryParam(errors map[string]string, name string, value string, tags string) {
+	if value == "" {
+		return
+	}
+	if message := validationMessage(value, tags); message != "" {
+		errors[name] = message
+	}
+}
+
+func validationMessage(value string, tags string) string {
+	err := utils.Validate.Var(value, tags)
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		return fmt.Sprintf("validation failed on '%s' tag", validationErrs[0].Tag())
+	} else if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func ValidateBatchPayload(ctx context.Context, payload *[]types.BankDataDetails) error {
+	if len(*payload) == 0 {
+		return fmt.Errorf("the batch is empty")
+	}
+	if len(*payload) > utils.BatchMaxItems {
+		return fmt.Errorf("the batch has %d entries, the limit is %d", len(*payload), utils.BatchMaxItems)
+	}
+	return nil
+}
+
+func ValidateLookupPayload(ctx context.Context, payload *types.LookupRequest) error {
+	if len(payload.SwiftCodes) == 0 {
+		return fmt.Errorf("no SWIFT codes to look up")
+	}
+	if len(payload.SwiftCodes) > utils.LookupMaxSwiftCodes {
+		return fmt.Errorf("the lookup has %d SWIFT codes, the limit is %d", len(payload.SwiftCodes), utils.LookupMaxSwiftCodes)
+	}
+	return nil
 }
 
 func ValidatePostSwiftCodePayload(ctx context.Context, payload *types.BankDataDetails) error {
+	payload.SwiftCode = utils.NormalizeSwiftCode(payload.SwiftCode)
 	if err := utils.Validate.Struct(payload); err != nil {
-		return fmt.Errorf("invalid payload structure: %w", err)
+		return fmt.Errorf("invalid payload structure: %w", newValidationError(err))
 	}
 
 	expectedCountryCode, err := utils.GetCountryCodeFromSwiftCode(payload.SwiftCode)
 	if err != nil {
-		return fmt.Errorf("invalid SWIFT code: %w", err)
+		return utils.ProblemError{Code: utils.ProblemInvalidSwiftCode, Err: fmt.Errorf("invalid SWIFT code: %w", err)}
 	}
 	if payload.CountryIso2 != expectedCountryCode {
-		return fmt.Errorf("countryISO2 '%s' does not match the country derived from SWIFT code '%s'", payload.CountryIso2, expectedCountryCode)
+		return utils.ProblemError{Code: utils.ProblemCountryMismatch, Err: fmt.Errorf("countryISO2 '%s' does not match the country derived from SWIFT code '%s'", payload.CountryIso2, expectedCountryCode)}
 	}
 	expectedCountryName := utils.GetCountryNameFromCountryCode(payload.CountryIso2)
 	payload.CountryName = strings.ToUpper(payload.CountryName)
 	if !strings.EqualFold(payload.CountryName, expectedCountryName) {
-		return fmt.Errorf("countryName '%s' does not match the country derived from countryISO2 '%s'", payload.CountryName, expectedCountryName)
+		return utils.ProblemError{Code: utils.ProblemCountryMismatch, Err: fmt.Errorf("countryName '%s' does not match the country derived from countryISO2 '%s'", payload.CountryName, expectedCountryName)}
 	}
 	if utils.Xor(payload.IsHeadquarter, strings.HasSuffix(payload.SwiftCode, utils.BranchSuffix)) {
-		return fmt.Errorf("isHeadquarter value '%v' does not match the swiftCode value '%s'", payload.IsHeadquarter, payload.SwiftCode)
+		return utils.ProblemError{Code: utils.ProblemHqMismatch, Err: fmt.Errorf("isHeadquarter value '%v' does not match the swiftCode value '%s'", payload.IsHeadquarter, payload.SwiftCode)}
 	}
 
 	return nil
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/DroppedHard/SWIFT-service/service/api"
@@ -105,6 +107,180 @@ func TestValidateSwiftCode(t *testing.T) {
 	}
 }
 
+func TestDecodeSwiftCode(t *testing.T) {
+	tests := []struct {
+		Description string
+		SwiftCode   string
+		Expected    types.SwiftCodeStructure
+	}{
+		{
+			Description: "BIC11 branch code",
+			SwiftCode:   "ALBPPLPWCUS",
+			Expected: types.SwiftCodeStructure{
+				SwiftCode:    "ALBPPLPWCUS",
+				Valid:        true,
+				Format:       utils.SwiftFormatBic11,
+				Normalized:   "ALBPPLPWCUS",
+				BankCode:     "ALBP",
+				CountryIso2:  "PL",
+				CountryName:  "POLAND",
+				LocationCode: "PW",
+				BranchCode:   "CUS",
+			},
+		},
+		{
+			Description: "BIC8 test code",
+			SwiftCode:   "ALBPPLP0",
+			Expected: types.SwiftCodeStructure{
+				SwiftCode:     "ALBPPLP0",
+				Valid:         true,
+				Format:        utils.SwiftFormatBic8,
+				Normalized:    "ALBPPLP0XXX",
+				BankCode:      "ALBP",
+				CountryIso2:   "PL",
+				CountryName:   "POLAND",
+				LocationCode:  "P0",
+				BranchCode:    "XXX",
+				IsHeadquarter: true,
+				IsTestCode:    true,
+			},
+		},
+		{
+			Description: "Lowercase code with whitespace",
+			SwiftCode:   " albpplpw ",
+			Expected: types.SwiftCodeStructure{
+				SwiftCode:     " albpplpw ",
+				Valid:         true,
+				Format:        utils.SwiftFormatBic8,
+				Normalized:    "ALBPPLPWXXX",
+				BankCode:      "ALBP",
+				CountryIso2:   "PL",
+				CountryName:   "POLAND",
+				LocationCode:  "PW",
+				BranchCode:    "XXX",
+				IsHeadquarter: true,
+			},
+		},
+		{
+			Description: "Passive participant",
+			SwiftCode:   "ALBPPLP1XXX",
+			Expected: types.SwiftCodeStructure{
+				SwiftCode:     "ALBPPLP1XXX",
+				Valid:         true,
+				Format:        utils.SwiftFormatBic11,
+				Normalized:    "ALBPPLP1XXX",
+				BankCode:      "ALBP",
+				CountryIso2:   "PL",
+				CountryName:   "POLAND",
+				LocationCode:  "P1",
+				BranchCode:    "XXX",
+				IsHeadquarter: true,
+				IsPassive:     true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			assert.Equal(t, test.Expected, api.DecodeSwiftCode(test.SwiftCode))
+		})
+	}
+
+	for _, swiftCode := range []string{"", "ALBPPL", "ALBPPLPWXXXX", "ALB)__XAXXX"} {
+		t.Run("Invalid "+swiftCode, func(t *testing.T) {
+			result := api.DecodeSwiftCode(swiftCode)
+			assert.False(t, result.Valid)
+			assert.NotEmpty(t, result.Error)
+			assert.Equal(t, swiftCode, result.SwiftCode)
+			assert.Empty(t, result.Normalized)
+		})
+	}
+}
+
+func TestValidateBankCode(t *testing.T) {
+	tests := []struct {
+		Description string
+		BankCode    string
+		ExpectedErr string
+	}{
+		{
+			Description: "Valid bank code",
+			BankCode:    "ALBP",
+		},
+		{
+			Description: "Lowercase bank code",
+			BankCode:    "albp",
+		},
+		{
+			Description: "Too short bank code",
+			BankCode:    "ALB",
+			ExpectedErr: "validation failed on 'len' tag",
+		},
+		{
+			Description: "Bank code with a digit",
+			BankCode:    "ALB1",
+			ExpectedErr: "validation failed on 'alpha' tag",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/banks/"+test.BankCode, nil)
+			req = mux.SetURLVars(req, map[string]string{utils.PathParamBankCode: test.BankCode})
+
+			err := api.ValidateBankCode(req)
+
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateIban(t *testing.T) {
+	tests := []struct {
+		Description string
+		Iban        string
+		ExpectedErr string
+	}{
+		{
+			Description: "Valid IBAN",
+			Iban:        "PL37249000500000000000000000",
+		},
+		{
+			Description: "Printed IBAN in lowercase",
+			Iban:        "de89 3704 0044 0532 0130 00",
+		},
+		{
+			Description: "Invalid check digit",
+			Iban:        "PL38249000500000000000000000",
+			ExpectedErr: "invalid IBAN",
+		},
+		{
+			Description: "Too short IBAN",
+			Iban:        "PL37",
+			ExpectedErr: "invalid IBAN",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/iban/bic", nil)
+			req = mux.SetURLVars(req, map[string]string{utils.PathParamIban: test.Iban})
+
+			err := api.ValidateIban(req)
+
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateCountryCode(t *testing.T) {
 	tests := []struct {
 		Description string
@@ -208,7 +384,7 @@ func TestValidatePostSwiftCodePayload(t *testing.T) {
 				},
 				CountryName: "United States",
 			},
-			ExpectedErr: "Field validation for 'SwiftCode' failed on the 'len' tag",
+			ExpectedErr: "swiftCode: validation failed on 'len' tag",
 		},
 	}
 
@@ -225,3 +401,303 @@ func TestValidatePostSwiftCodePayload(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePostSwiftCodePayloadNormalizesSwiftCode(t *testing.T) {
+	payload := &types.BankDataDetails{
+		BankDataCore: types.BankDataCore{
+			SwiftCode:     " albpplpw",
+			Address:       "Valid address",
+			BankName:      "Valid bank name",
+			CountryIso2:   "PL",
+			IsHeadquarter: true,
+		},
+		CountryName: "Poland",
+	}
+
+	assert.NoError(t, api.ValidatePostSwiftCodePayload(context.Background(), payload))
+	assert.Equal(t, "ALBPPLPWXXX", payload.SwiftCode)
+}
+
+func TestNormalizeSwiftCodePath(t *testing.T) {
+	tests := []struct {
+		Description  string
+		SwiftCode    string
+		ExpectedCode string
+	}{
+		{
+			Description:  "Canonical SWIFT code",
+			SwiftCode:    "ALBPPLPWCUS",
+			ExpectedCode: "ALBPPLPWCUS",
+		},
+		{
+			Description:  "Lowercase BIC8",
+			SwiftCode:    "albpplpw",
+			ExpectedCode: "ALBPPLPWXXX",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/"+test.SwiftCode, nil)
+			req = mux.SetURLVars(req, map[string]string{utils.PathParamSwiftCode: test.SwiftCode})
+
+			normalized, path := api.NormalizeSwiftCodePath(req)
+
+			assert.Equal(t, test.ExpectedCode, mux.Vars(normalized)[utils.PathParamSwiftCode])
+			assert.Equal(t, "/v1/swift-codes/"+test.ExpectedCode, path)
+			assert.Equal(t, test.SwiftCode, mux.Vars(req)[utils.PathParamSwiftCode])
+		})
+	}
+}
+
+func TestValidateBatchPayload(t *testing.T) {
+	tests := []struct {
+		Description string
+		Payload     []types.BankDataDetails
+		ExpectedErr string
+	}{
+		{
+			Description: "Valid batch",
+			Payload:     make([]types.BankDataDetails, 2),
+		},
+		{
+			Description: "Empty batch",
+			Payload:     []types.BankDataDetails{},
+			ExpectedErr: "the batch is empty",
+		},
+		{
+			Description: "Too many entries",
+			Payload:     make([]types.BankDataDetails, utils.BatchMaxItems+1),
+			ExpectedErr: "the limit is 1000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			err := api.ValidateBatchPayload(context.Background(), &test.Payload)
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateLookupPayload(t *testing.T) {
+	tests := []struct {
+		Description string
+		Payload     types.LookupRequest
+		ExpectedErr string
+	}{
+		{
+			Description: "Valid lookup",
+			Payload:     types.LookupRequest{SwiftCodes: []string{"ALBPPLPWXXX", "INVALID"}},
+		},
+		{
+			Description: "No SWIFT codes",
+			Payload:     types.LookupRequest{},
+			ExpectedErr: "no SWIFT codes to look up",
+		},
+		{
+			Description: "Too many SWIFT codes",
+			Payload:     types.LookupRequest{SwiftCodes: make([]string, utils.LookupMaxSwiftCodes+1)},
+			ExpectedErr: "the limit is 1000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			err := api.ValidateLookupPayload(context.Background(), &test.Payload)
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseBankDataFilter(t *testing.T) {
+	isHeadquarter := false
+	tests := []struct {
+		Description string
+		Query       string
+		Expected    types.BankDataFilter
+		ExpectedErr string
+	}{
+		{
+			Description: "All filters",
+			Query:       "country=de&bankCode=brex&isHeadquarter=false&bankName=%20Bank%20AG%20",
+			Expected:    types.BankDataFilter{CountryIso2: "DE", BankCode: "BREX", IsHeadquarter: &isHeadquarter, BankName: "Bank AG"},
+		},
+		{
+			Description: "Bank code only",
+			Query:       "bankCode=BREX",
+			Expected:    types.BankDataFilter{BankCode: "BREX"},
+		},
+		{
+			Description: "No country nor bank code",
+			Query:       "isHeadquarter=true",
+			ExpectedErr: "at least one of the country and bankCode query parameters is required",
+		},
+		{
+			Description: "Bank code with digits",
+			Query:       "bankCode=BR3X",
+			ExpectedErr: "bankCode: validation failed on 'alpha' tag",
+		},
+		{
+			Description: "Bank name too long",
+			Query:       "country=PL&bankName=" + strings.Repeat("A", utils.BankNameMaxLength+1),
+			ExpectedErr: "bankName: validation failed on 'max' tag",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			query, _ := url.ParseQuery(test.Query)
+			filter, err := api.ParseBankDataFilter(query)
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, filter)
+		})
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		Description string
+		Query       string
+		Expected    types.SearchQuery
+		ExpectedErr string
+	}{
+		{
+			Description: "Default limit",
+			Query:       "q=%20Bank%20%C5%81%C3%B3d%C5%BA%20",
+			Expected:    types.SearchQuery{Query: "Bank Łódź", Terms: []string{"BANK", "LODZ"}, Limit: utils.SearchDefaultLimit},
+		},
+		{
+			Description: "Custom limit",
+			Query:       "q=warszawa&limit=5",
+			Expected:    types.SearchQuery{Query: "warszawa", Terms: []string{"WARSZAWA"}, Limit: 5},
+		},
+		{
+			Description: "Missing query",
+			Query:       "limit=5",
+			ExpectedErr: "the q query parameter is required",
+		},
+		{
+			Description: "Query too long",
+			Query:       "q=" + strings.Repeat("A", utils.SearchMaxQueryLength+1),
+			ExpectedErr: "the q query parameter can have at most 200 characters",
+		},
+		{
+			Description: "No searchable words",
+			Query:       "q=a%20-%20b",
+			ExpectedErr: "the q query parameter has to contain a word of at least 2 letters or digits",
+		},
+		{
+			Description: "Limit out of range",
+			Query:       "q=bank&limit=101",
+			ExpectedErr: "limit has to be a number between 1 and 100",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			query, _ := url.ParseQuery(test.Query)
+			search, err := api.ParseSearchQuery(query)
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, search)
+		})
+	}
+}
+
+func TestParseAutocompleteQuery(t *testing.T) {
+	tests := []struct {
+		Description string
+		Query       string
+		Expected    types.AutocompleteQuery
+		ExpectedErr string
+	}{
+		{
+			Description: "Default limit",
+			Query:       "prefix=%20bank%20p%C3%B3l",
+			Expected:    types.AutocompleteQuery{Prefix: "BANK POL", Limit: utils.AutocompleteDefaultLimit},
+		},
+		{
+			Description: "Custom limit",
+			Query:       "prefix=albp&limit=5",
+			Expected:    types.AutocompleteQuery{Prefix: "ALBP", Limit: 5},
+		},
+		{
+			Description: "Missing prefix",
+			Query:       "limit=5",
+			ExpectedErr: "the prefix query parameter has to contain a letter or a digit",
+		},
+		{
+			Description: "Prefix too long",
+			Query:       "prefix=" + strings.Repeat("A", utils.AutocompleteMaxPrefixLength+1),
+			ExpectedErr: "the prefix query parameter can have at most 100 characters",
+		},
+		{
+			Description: "Limit out of range",
+			Query:       "prefix=albp&limit=0",
+			ExpectedErr: "limit has to be a number between 1 and 50",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			query, _ := url.ParseQuery(test.Query)
+			autocomplete, err := api.ParseAutocompleteQuery(query)
+			if test.ExpectedErr != "" {
+				assert.ErrorContains(t, err, test.ExpectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, autocomplete)
+		})
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		Description string
+		IfMatch     string
+		Expected    types.VersionCondition
+		ExpectedErr bool
+	}{
+		{Description: "No header", IfMatch: "", Expected: types.VersionCondition{}},
+		{Description: "Any version", IfMatch: "*", Expected: types.VersionCondition{}},
+		{Description: "Quoted version", IfMatch: `"3"`, Expected: types.VersionCondition{Conditional: true, Versions: []int64{3}}},
+		{Description: "Read ETag", IfMatch: `"3-9f86d081884c7d65"`, Expected: types.VersionCondition{Conditional: true, Versions: []int64{3}}},
+		{Description: "List of entity tags", IfMatch: `"3-9f86d0", "4"`, Expected: types.VersionCondition{Conditional: true, Versions: []int64{3, 4}}},
+		{Description: "Weak entity tag never matches", IfMatch: `W/"3"`, Expected: types.VersionCondition{Conditional: true}},
+		{Description: "Weak entity tag in a list", IfMatch: `W/"3", "4"`, Expected: types.VersionCondition{Conditional: true, Versions: []int64{4}}},
+		{Description: "Entity tag without a version", IfMatch: `"9f86d081884c7d65"`, Expected: types.VersionCondition{Conditional: true}},
+		{Description: "Zero version", IfMatch: `"0"`, Expected: types.VersionCondition{Conditional: true}},
+		{Description: "Unquoted version", IfMatch: "3", ExpectedErr: true},
+		{Description: "Missing closing quote", IfMatch: `"3`, ExpectedErr: true},
+		{Description: "Missing comma", IfMatch: `"3" "4"`, ExpectedErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Description, func(t *testing.T) {
+			condition, err := api.ParseIfMatch(test.IfMatch)
+			if test.ExpectedErr {
+				assert.ErrorContains(t, err, "invalid If-Match header")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, condition)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/DroppedHard/SWIFT-service/service/api"
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+)
+
+// IdempotencyMiddleware replays the stored response of a request retried with the same Idempotency-Key header.
+// The key is bound to the hash of the method, URI and body of its first request - reusing it for another request
+// is rejected with 422, and retrying while the first request is still handled with 409. Server errors are not stored,
+// so such a request can be retried. Requests without the header are passed through.
+func IdempotencyMiddleware(store types.IdempotencyStore) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(utils.HeaderIdempotencyKey)
+			if key == "" {
+				next(w, r)
+				return
+			}
+			if len(key) > utils.IdempotencyKeyMaxLength {
+				api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidIdemKey, fmt.Errorf("the %s header can have at most %d characters", utils.HeaderIdempotencyKey, utils.IdempotencyKeyMaxLength))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidBody, fmt.Errorf("failed to read request body: %v", err))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			requestHash := hashRequest(r, body)
+
+			stored, err := store.ReserveIdempotencyKey(r.Context(), key, types.IdempotencyRecord{RequestHash: requestHash})
+			if err != nil {
+				api.WriteError(w, http.StatusInternalServerError, utils.ProblemStoreUnavailable, err)
+				return
+			}
+			if stored != nil {
+				replayIdempotentResponse(w, key, requestHash, stored)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w}
+			defer func() {
+				// The context of the request may already be canceled, so the key is settled with a fresh one.
+				ctx := context.WithoutCancel(r.Context())
+				if recorder.status == 0 || recorder.status >= http.StatusInternalServerError {
+					if err := store.DeleteIdempotencyKey(ctx, key); err != nil {
+						log.Println(err)
+					}
+					return
+				}
+				record := types.IdempotencyRecord{
+					RequestHash: requestHash,
+					Status:      recorder.status,
+					Header:      recorder.header,
+					Body:        recorder.body.Bytes(),
+				}
+				if err := store.SaveIdempotencyRecord(ctx, key, record); err != nil {
+					log.Println(err)
+				}
+			}()
+			next(recorder, r)
+		}
+	}
+}
+
+func hashRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.RequestURI())
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, key string, requestHash string, stored *types.IdempotencyRecord) {
+	if stored.RequestHash != requestHash {
+		api.WriteError(w, http.StatusUnprocessableEntity, utils.ProblemIdemKeyReused, fmt.Errorf("the idempotency key %s was already used for a different request", key))
+		return
+	}
+	if stored.Status == 0 {
+		api.WriteError(w, http.StatusConflict, utils.ProblemIdemKeyInUse, fmt.Errorf("the request with the idempotency key %s is still being handled", key))
+		return
+	}
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(utils.HeaderIdempotentReplay, "true")
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+}
+
+// responseRecorder passes the response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status != 0 {
+		return
+	}
+	r.status = status
+	r.header = r.ResponseWriter.Header().Clone()
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
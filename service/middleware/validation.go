@@ -6,16 +6,18 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"reflect"
 
 	"github.com/DroppedHard/SWIFT-service/service/api"
+	"github.com/DroppedHard/SWIFT-service/utils"
 )
 
 func CustomPathParameterValidationMiddleware(validateFn func(r *http.Request) error) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			if err := validateFn(r); err != nil {
-				api.WriteError(w, http.StatusBadRequest, err)
+				api.WriteError(w, http.StatusBadRequest, api.ProblemCode(err, utils.ProblemInvalidPathParam), err)
 				return
 			}
 			next(w, r)
@@ -23,20 +25,37 @@ func CustomPathParameterValidationMiddleware(validateFn func(r *http.Request) er
 	}
 }
 
+// QueryParameterValidationMiddleware parses and validates the query parameters, and passes the result
+// to the next handler through the request context, keyed by its type.
+func QueryParameterValidationMiddleware[T any](parseFn func(query url.Values) (T, error)) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			parsed, err := parseFn(r.URL.Query())
+			if err != nil {
+				api.WriteError(w, http.StatusBadRequest, api.ProblemCode(err, utils.ProblemInvalidQuery), err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), reflect.TypeOf(parsed), parsed)
+			next(w, r.WithContext(ctx))
+		}
+	}
+}
+
 func BodyValidationMiddleware[T any](validateFn func(context.Context, *T) error) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			var payload T
 
 			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-				api.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid JSON payload: %v", err))
+				api.WriteError(w, http.StatusBadRequest, utils.ProblemInvalidBody, fmt.Errorf("invalid JSON payload: %v", err))
 				log.Println(fmt.Errorf("invalid JSON payload: %v", err))
 				return
 			}
 
 			if validateFn != nil {
 				if err := validateFn(r.Context(), &payload); err != nil {
-					api.WriteError(w, http.StatusBadRequest, fmt.Errorf("validation error: %v", err))
+					api.WriteError(w, http.StatusBadRequest, api.ProblemCode(err, utils.ProblemValidationFailed), fmt.Errorf("validation error: %w", err))
 					log.Println(fmt.Errorf("validation error: %v", err))
 					return
 				}
@@ -47,3 +66,54 @@ func BodyValidationMiddleware[T any](validateFn func(context.Context, *T) error)
 		}
 	}
 }
+
+// SwiftCodeNormalizationMiddleware passes the canonical SWIFT code to the next handler
+// and points the Content-Location header of successful responses to the canonical resource.
+func SwiftCodeNormalizationMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r, path := api.NormalizeSwiftCodePath(r)
+		next(&contentLocationWriter{ResponseWriter: w, location: path}, r)
+	}
+}
+
+// contentLocationWriter sets the Content-Location header only when a 2xx status is written, so rejected SWIFT codes
+// and failed requests never point to a resource.
+type contentLocationWriter struct {
+	http.ResponseWriter
+	location    string
+	wroteHeader bool
+}
+
+func (w *contentLocationWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if status >= http.StatusOK && status < http.StatusMultipleChoices {
+		w.Header().Set(utils.HeaderContentLocation, w.location)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *contentLocationWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// ContentNegotiationMiddleware passes the media type negotiated with the Accept header to the next handler
+// through the request context, keyed by its type.
+func ContentNegotiationMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accept := r.Header.Get(utils.HeaderAccept)
+		mediaType, ok := api.NegotiateMediaType(accept)
+		if !ok {
+			api.WriteError(w, http.StatusNotAcceptable, utils.ProblemNotAcceptable, fmt.Errorf("cannot produce any of the accepted media types '%s', supported are %v", accept, api.SupportedMediaTypes))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), reflect.TypeOf(mediaType), mediaType)
+		next(w, r.WithContext(ctx))
+	}
+}
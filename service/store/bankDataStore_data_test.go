@@ -0,0 +1,81 @@
+package store_test
+
+import (
+	"github.com/DroppedHard/SWIFT-service/types"
+)
+
+var StoreTestBankData = []types.BankDataDetails{
+	{
+		BankDataCore: types.BankDataCore{
+			Address:       "PROSTA 18 WARSZAWA",
+			BankName:      "BREX BANK SPOLKA AKCYJNA",
+			CountryIso2:   "PL",
+			IsHeadquarter: true,
+			SwiftCode:     "BREXPLPWXXX",
+		},
+		CountryName: "POLAND",
+	},
+	{
+		BankDataCore: types.BankDataCore{
+			Address:       "KRAKOWSKA 1 KRAKOW",
+			BankName:      "BREX BANK SPOLKA AKCYJNA",
+			CountryIso2:   "PL",
+			IsHeadquarter: false,
+			SwiftCode:     "BREXPLPWKRK",
+		},
+		CountryName: "POLAND",
+	},
+	{
+		BankDataCore: types.BankDataCore{
+			Address:       "GDANSKA 2 GDANSK",
+			BankName:      "BREX BANK SPOLKA AKCYJNA",
+			CountryIso2:   "PL",
+			IsHeadquarter: false,
+			SwiftCode:     "BREXPLPWGDA",
+		},
+		CountryName: "POLAND",
+	},
+	{
+		BankDataCore: types.BankDataCore{
+			Address:       "TAUNUSANLAGE 12 FRANKFURT AM MAIN",
+			BankName:      "BREX BANK AG",
+			CountryIso2:   "DE",
+			IsHeadquarter: true,
+			SwiftCode:     "BREXDEFFXXX",
+		},
+		CountryName: "GERMANY",
+	},
+	{
+		BankDataCore: types.BankDataCore{
+			Address:       "BRANCH STREET 5 BERLIN",
+			BankName:      "BREX BANK AG",
+			CountryIso2:   "DE",
+			IsHeadquarter: false,
+			SwiftCode:     "BREXDEFFBER",
+		},
+		CountryName: "GERMANY",
+	},
+}
+
+var StoreNewBankData = types.BankDataDetails{
+	BankDataCore: types.BankDataCore{
+		Address:       "LODZKA 3 LODZ",
+		BankName:      "BREX BANK SPOLKA AKCYJNA",
+		CountryIso2:   "PL",
+		IsHeadquarter: false,
+		SwiftCode:     "BREXPLPWLOD",
+	},
+	CountryName: "POLAND",
+}
+
+var StoreNationalBankCode = types.NationalBankCode{
+	CountryIso2:      "PL",
+	NationalBankCode: "9990001",
+	SwiftCode:        "BREXPLPWXXX",
+}
+
+var StoreNonexistentSwiftCodes = []string{
+	"BREXPLPWABC",
+	"BREXDEFF123",
+	"BREXGB2LXXX",
+}
@@ -0,0 +1,885 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DroppedHard/SWIFT-service/config"
+	"github.com/DroppedHard/SWIFT-service/db"
+	"github.com/DroppedHard/SWIFT-service/service/store"
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+// BankDataStoreTestSuite holds behavioural tests shared by every types.BankDataStore implementation.
+type BankDataStoreTestSuite struct {
+	suite.Suite
+	store      types.BankDataStore
+	setupStore func() types.BankDataStore
+	closeStore func()
+}
+
+func TestMemoryStoreSuite(t *testing.T) {
+	suite.Run(t, &BankDataStoreTestSuite{
+		setupStore: func() types.BankDataStore { return store.NewMemoryStore() },
+	})
+}
+
+func TestRedisStoreBehaviourSuite(t *testing.T) {
+	var rdb *redis.Client
+	suite.Run(t, &BankDataStoreTestSuite{
+		setupStore: func() types.BankDataStore {
+			rdb = db.NewRedisStorage(&redis.Options{
+				Addr:     fmt.Sprintf("%s:%s", config.Envs.DBHost, config.Envs.DBPort),
+				Password: config.Envs.DBPassword,
+				DB:       config.Envs.DBTestNum,
+			})
+			return store.NewStore(rdb)
+		},
+		closeStore: func() { rdb.Close() },
+	})
+}
+
+func TestPostgresStoreSuite(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pool, err := db.NewPostgresStorage(ctx, config.Envs.PostgresTestURL)
+	if err != nil {
+		t.Skipf("postgres test database unavailable: %v", err)
+	}
+	if err := db.MigratePostgres(ctx, pool); err != nil {
+		t.Fatalf("failed to migrate postgres test database: %v", err)
+	}
+	suite.Run(t, &BankDataStoreTestSuite{
+		setupStore: func() types.BankDataStore { return store.NewPostgresStore(pool) },
+		closeStore: pool.Close,
+	})
+}
+
+func TestBoltStoreSuite(t *testing.T) {
+	boltDB, err := db.NewBoltStorage(filepath.Join(t.TempDir(), "swift.db"))
+	if err != nil {
+		t.Fatalf("failed to open bolt test file: %v", err)
+	}
+	suite.Run(t, &BankDataStoreTestSuite{
+		setupStore: func() types.BankDataStore { return store.NewBoltStore(boltDB) },
+		closeStore: func() { boltDB.Close() },
+	})
+}
+
+func (suite *BankDataStoreTestSuite) SetupSuite() {
+	suite.store = suite.setupStore()
+	ctx := context.Background()
+	for _, entry := range StoreTestBankData {
+		suite.Require().NoError(suite.store.SaveBankData(ctx, entry))
+	}
+}
+
+func (suite *BankDataStoreTestSuite) TearDownSuite() {
+	ctx := context.Background()
+	for _, entry := range StoreTestBankData {
+		suite.NoError(suite.store.DeleteBankData(ctx, entry.SwiftCode, 0))
+	}
+	if suite.closeStore != nil {
+		suite.closeStore()
+	}
+}
+
+func (suite *BankDataStoreTestSuite) expectedBanks(matches func(swiftCode string) bool) []types.BankDataCore {
+	var banks []types.BankDataCore
+	for _, entry := range StoreTestBankData {
+		if matches(entry.SwiftCode) {
+			banks = append(banks, entry.BankDataCore)
+		}
+	}
+	return banks
+}
+
+// assertMatchingBanks checks that every expected entry was found and that nothing outside of the lookup
+// criteria was returned - other entries may already live in a shared test database.
+func (suite *BankDataStoreTestSuite) assertMatchingBanks(expected []types.BankDataCore, actual []types.BankDataCore, matches func(swiftCode string) bool) {
+	suite.Subset(actual, expected)
+	for _, bank := range actual {
+		suite.True(matches(bank.SwiftCode), "unexpected SWIFT code %s in results", bank.SwiftCode)
+	}
+}
+
+// assertBankDetails checks that the bank data was found with the fixture's content, at any version set by the store.
+func (suite *BankDataStoreTestSuite) assertBankDetails(expected types.BankDataDetails, actual *types.BankDataDetails) {
+	if suite.NotNil(actual) {
+		suite.Positive(actual.Version)
+		suite.Equal(withoutVersions(expected), withoutVersions(*actual))
+	}
+}
+
+// withoutVersions clears the versions set by the store, so the bank data can be compared with the fixtures.
+func withoutVersions(banks ...types.BankDataDetails) []types.BankDataDetails {
+	cleared := make([]types.BankDataDetails, len(banks))
+	for i, bank := range banks {
+		bank.Version = 0
+		cleared[i] = bank
+	}
+	return cleared
+}
+
+func (suite *BankDataStoreTestSuite) assertCanceledContext(call func(ctx context.Context) error) {
+	suite.Run("Canceled Context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		suite.ErrorContains(call(ctx), "context canceled")
+	})
+	suite.Run("Expired Context", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		suite.ErrorContains(call(ctx), "context deadline exceeded")
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestPing() {
+	suite.NoError(suite.store.Ping(context.Background()))
+}
+
+func (suite *BankDataStoreTestSuite) TestDoesSwiftCodeExist() {
+	ctx := context.Background()
+	for _, entry := range StoreTestBankData {
+		suite.Run(entry.SwiftCode+" should exist", func() {
+			count, err := suite.store.DoesSwiftCodeExist(ctx, entry.SwiftCode)
+			suite.NoError(err)
+			suite.Equal(int64(1), count)
+		})
+	}
+	for _, code := range StoreNonexistentSwiftCodes {
+		suite.Run(code+" should not exist", func() {
+			count, err := suite.store.DoesSwiftCodeExist(ctx, code)
+			suite.NoError(err)
+			suite.Equal(int64(0), count)
+		})
+	}
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		count, err := suite.store.DoesSwiftCodeExist(ctx, StoreTestBankData[0].SwiftCode)
+		suite.Equal(int64(utils.SwiftCodeExistsError), count)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestSaveAndDeleteBankData() {
+	ctx := context.Background()
+	entry := StoreNewBankData
+
+	suite.Require().NoError(suite.store.SaveBankData(ctx, entry))
+	data, err := suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
+	suite.NoError(err)
+	suite.assertBankDetails(entry, data)
+
+	updated := entry
+	updated.Address = "LODZKA 4 LODZ"
+	suite.Require().NoError(suite.store.SaveBankData(ctx, updated))
+	data, err = suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
+	suite.NoError(err)
+	suite.assertBankDetails(updated, data)
+
+	suite.Require().NoError(suite.store.DeleteBankData(ctx, entry.SwiftCode, 0))
+	data, err = suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
+	suite.NoError(err)
+	suite.Nil(data)
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		return suite.store.SaveBankData(ctx, entry)
+	})
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		return suite.store.DeleteBankData(ctx, entry.SwiftCode, 0)
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestCreateBankData() {
+	ctx := context.Background()
+	entry := StoreNewBankData
+	defer suite.store.DeleteBankData(ctx, entry.SwiftCode, 0)
+
+	suite.Run("Existing SWIFT code is not overwritten", func() {
+		existing := StoreTestBankData[0]
+		overwrite := existing
+		overwrite.Address = "OVERWRITTEN"
+
+		created, err := suite.store.CreateBankData(ctx, overwrite)
+		suite.NoError(err)
+		suite.False(created)
+
+		data, err := suite.store.FindBankDetailsBySwiftCode(ctx, existing.SwiftCode)
+		suite.NoError(err)
+		suite.assertBankDetails(existing, data)
+	})
+
+	suite.Run("Concurrent creates", func() {
+		const attempts = 20
+		results := make(chan bool, attempts)
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				created, err := suite.store.CreateBankData(ctx, entry)
+				suite.NoError(err)
+				results <- created
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		createdCount := 0
+		for created := range results {
+			if created {
+				createdCount++
+			}
+		}
+		suite.Equal(1, createdCount)
+
+		data, err := suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
+		suite.NoError(err)
+		suite.assertBankDetails(entry, data)
+	})
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		_, err := suite.store.CreateBankData(ctx, entry)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestCreateBankDataBatch() {
+	ctx := context.Background()
+	entry := StoreNewBankData
+	defer suite.store.DeleteBankData(ctx, entry.SwiftCode, 0)
+
+	suite.Run("Batch with existing SWIFT code is not stored", func() {
+		existing := StoreTestBankData[0]
+		overwrite := existing
+		overwrite.Address = "OVERWRITTEN"
+
+		conflicts, err := suite.store.CreateBankDataBatch(ctx, []types.BankDataDetails{entry, overwrite})
+		suite.NoError(err)
+		suite.Equal([]string{existing.SwiftCode}, conflicts)
+
+		exists, err := suite.store.DoesSwiftCodeExist(ctx, entry.SwiftCode)
+		suite.NoError(err)
+		suite.Equal(int64(0), exists)
+		data, err := suite.store.FindBankDetailsBySwiftCode(ctx, existing.SwiftCode)
+		suite.NoError(err)
+		suite.assertBankDetails(existing, data)
+	})
+
+	suite.Run("Batch of new SWIFT codes is stored", func() {
+		conflicts, err := suite.store.CreateBankDataBatch(ctx, []types.BankDataDetails{entry})
+		suite.NoError(err)
+		suite.Empty(conflicts)
+
+		data, err := suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
+		suite.NoError(err)
+		suite.assertBankDetails(entry, data)
+	})
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		_, err := suite.store.CreateBankDataBatch(ctx, []types.BankDataDetails{entry})
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestUpdateBankData() {
+	ctx := context.Background()
+
+	suite.Run("Existing SWIFT code is updated", func() {
+		existing := StoreTestBankData[0]
+		defer suite.store.SaveBankData(ctx, existing)
+		updatedData := existing
+		updatedData.Address = "UPDATED"
+
+		updated, err := suite.store.UpdateBankData(ctx, updatedData, 0)
+		suite.NoError(err)
+		suite.True(updated)
+
+		data, err := suite.store.FindBankDetailsBySwiftCode(ctx, existing.SwiftCode)
+		suite.NoError(err)
+		suite.assertBankDetails(updatedData, data)
+	})
+
+	suite.Run("Missing SWIFT code is not created", func() {
+		entry := StoreNewBankData
+
+		updated, err := suite.store.UpdateBankData(ctx, entry, 0)
+		suite.NoError(err)
+		suite.False(updated)
+
+		exists, err := suite.store.DoesSwiftCodeExist(ctx, entry.SwiftCode)
+		suite.NoError(err)
+		suite.Equal(int64(0), exists)
+	})
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		_, err := suite.store.UpdateBankData(ctx, StoreTestBankData[0], 0)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestConditionalWrites() {
+	ctx := context.Background()
+	entry := StoreNewBankData
+	defer suite.store.DeleteBankData(ctx, entry.SwiftCode, 0)
+	version := func() int64 {
+		data, err := suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
+		suite.Require().NoError(err)
+		suite.Require().NotNil(data)
+		return data.Version
+	}
+	var mismatchErr utils.VersionMismatchError
+
+	suite.Require().NoError(suite.store.SaveBankData(ctx, entry))
+	suite.Equal(int64(1), version())
+	suite.Require().NoError(suite.store.SaveBankData(ctx, entry))
+	suite.Equal(int64(2), version())
+
+	suite.Run("Update at the expected version", func() {
+		updatedData := entry
+		updatedData.Address = "LODZKA 5 LODZ"
+
+		updated, err := suite.store.UpdateBankData(ctx, updatedData, 2)
+		suite.NoError(err)
+		suite.True(updated)
+		suite.Equal(int64(3), version())
+	})
+
+	suite.Run("Update at a stale version", func() {
+		stale := entry
+		stale.Address = "STALE"
+
+		updated, err := suite.store.UpdateBankData(ctx, stale, 2)
+		suite.ErrorAs(err, &mismatchErr)
+		suite.False(updated)
+
+		data, err := suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
+		suite.NoError(err)
+		suite.Equal("LODZKA 5 LODZ", data.Address)
+		suite.Equal(int64(3), data.Version)
+	})
+
+	suite.Run("Concurrent updates at the same version", func() {
+		const attempts = 10
+		results := make(chan bool, attempts)
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				concurrent := entry
+				concurrent.Address = fmt.Sprintf("LODZKA %d LODZ", 10+i)
+				updated, err := suite.store.UpdateBankData(ctx, concurrent, 3)
+				if err != nil {
+					suite.ErrorAs(err, &utils.VersionMismatchError{})
+				}
+				results <- updated
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		updatedCount := 0
+		for updated := range results {
+			if updated {
+				updatedCount++
+			}
+		}
+		suite.Equal(1, updatedCount)
+		suite.Equal(int64(4), version())
+	})
+
+	suite.Run("Delete at a stale version", func() {
+		suite.ErrorAs(suite.store.DeleteBankData(ctx, entry.SwiftCode, 3), &mismatchErr)
+
+		exists, err := suite.store.DoesSwiftCodeExist(ctx, entry.SwiftCode)
+		suite.NoError(err)
+		suite.Equal(int64(1), exists)
+	})
+
+	suite.Run("Delete at the expected version", func() {
+		suite.NoError(suite.store.DeleteBankData(ctx, entry.SwiftCode, 4))
+
+		exists, err := suite.store.DoesSwiftCodeExist(ctx, entry.SwiftCode)
+		suite.NoError(err)
+		suite.Equal(int64(0), exists)
+	})
+
+	suite.Run("Missing SWIFT code", func() {
+		updated, err := suite.store.UpdateBankData(ctx, entry, 4)
+		suite.ErrorAs(err, &mismatchErr)
+		suite.False(updated)
+		suite.ErrorAs(suite.store.DeleteBankData(ctx, entry.SwiftCode, 4), &mismatchErr)
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestFindBankDetailsBySwiftCode() {
+	ctx := context.Background()
+	for _, entry := range StoreTestBankData {
+		suite.Run(entry.SwiftCode, func() {
+			data, err := suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
+			suite.NoError(err)
+			suite.assertBankDetails(entry, data)
+		})
+	}
+	for _, code := range StoreNonexistentSwiftCodes {
+		suite.Run(code+" not found", func() {
+			data, err := suite.store.FindBankDetailsBySwiftCode(ctx, code)
+			suite.NoError(err)
+			suite.Nil(data)
+		})
+	}
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		data, err := suite.store.FindBankDetailsBySwiftCode(ctx, StoreTestBankData[0].SwiftCode)
+		suite.Nil(data)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestFindBanksDetailsBySwiftCodes() {
+	ctx := context.Background()
+	swiftCodes := []string{StoreTestBankData[3].SwiftCode, StoreNonexistentSwiftCodes[0], StoreTestBankData[0].SwiftCode}
+
+	banks, err := suite.store.FindBanksDetailsBySwiftCodes(ctx, swiftCodes)
+	suite.NoError(err)
+	suite.ElementsMatch([]types.BankDataDetails{StoreTestBankData[3], StoreTestBankData[0]}, withoutVersions(banks...))
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		banks, err := suite.store.FindBanksDetailsBySwiftCodes(ctx, swiftCodes)
+		suite.Nil(banks)
+		return err
+	})
+}
+
+// TestFindCountriesStats allows for higher counts than the test data alone, as other entries may already live in a shared test database.
+func (suite *BankDataStoreTestSuite) TestFindCountriesStats() {
+	ctx := context.Background()
+	expected := make(map[string]types.CountryStats)
+	for _, entry := range StoreTestBankData {
+		stats := expected[entry.CountryIso2]
+		stats.Total++
+		if entry.IsHeadquarter {
+			stats.Headquarters++
+		} else {
+			stats.Branches++
+		}
+		expected[entry.CountryIso2] = stats
+	}
+
+	countries, err := suite.store.FindCountriesStats(ctx)
+	suite.NoError(err)
+	suite.IsIncreasing(countryCodes(countries))
+	found := 0
+	for _, country := range countries {
+		suite.Equal(country.Total, country.Headquarters+country.Branches)
+		suite.Equal(utils.GetCountryNameFromCountryCode(country.CountryIso2), country.CountryName)
+		if stats, ok := expected[country.CountryIso2]; ok {
+			found++
+			suite.GreaterOrEqual(country.Headquarters, stats.Headquarters)
+			suite.GreaterOrEqual(country.Branches, stats.Branches)
+		}
+	}
+	suite.Equal(len(expected), found)
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		countries, err := suite.store.FindCountriesStats(ctx)
+		suite.Nil(countries)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestExportBankData() {
+	ctx := context.Background()
+	export := func(filter types.ExportFilter) []types.BankDataRecord {
+		var records []types.BankDataRecord
+		suite.Require().NoError(suite.store.ExportBankData(ctx, filter, func(record types.BankDataRecord) error {
+			records = append(records, record)
+			return nil
+		}))
+		return records
+	}
+	fixtures := func(records []types.BankDataRecord) []types.BankDataDetails {
+		var banks []types.BankDataDetails
+		for _, record := range records {
+			for _, entry := range StoreTestBankData {
+				if record.SwiftCode == entry.SwiftCode {
+					suite.NotNil(record.UpdatedAt, "missing modification time of %s", record.SwiftCode)
+					banks = append(banks, withoutVersions(record.BankDataDetails)...)
+				}
+			}
+		}
+		return banks
+	}
+
+	suite.Run("All records", func() {
+		suite.ElementsMatch(StoreTestBankData, fixtures(export(types.ExportFilter{})))
+	})
+
+	suite.Run("Country filter", func() {
+		records := export(types.ExportFilter{CountryIso2: "DE"})
+		for _, record := range records {
+			suite.Equal("DE", record.CountryIso2)
+		}
+		var expected []types.BankDataDetails
+		for _, entry := range StoreTestBankData {
+			if entry.CountryIso2 == "DE" {
+				expected = append(expected, entry)
+			}
+		}
+		suite.ElementsMatch(expected, fixtures(records))
+	})
+
+	suite.Run("Country of the SWIFT code", func() {
+		entry := StoreNewBankData
+		entry.CountryIso2 = "DE"
+		suite.Require().NoError(suite.store.SaveBankData(ctx, entry))
+		defer suite.store.DeleteBankData(ctx, entry.SwiftCode, 0)
+		swiftCodes := func(records []types.BankDataRecord) []string {
+			var codes []string
+			for _, record := range records {
+				codes = append(codes, record.SwiftCode)
+			}
+			return codes
+		}
+
+		suite.Contains(swiftCodes(export(types.ExportFilter{CountryIso2: "PL"})), entry.SwiftCode)
+		suite.NotContains(swiftCodes(export(types.ExportFilter{CountryIso2: "DE"})), entry.SwiftCode)
+	})
+
+	suite.Run("Modified since filter", func() {
+		suite.ElementsMatch(StoreTestBankData, fixtures(export(types.ExportFilter{ModifiedSince: time.Now().Add(-time.Hour)})))
+		suite.Empty(fixtures(export(types.ExportFilter{ModifiedSince: time.Now().Add(time.Hour)})))
+	})
+
+	suite.Run("Records over many batches", func() {
+		entries := make([]types.BankDataDetails, utils.ExportBatchSize+1)
+		for i := range entries {
+			entries[i] = types.BankDataDetails{
+				BankDataCore: types.BankDataCore{
+					Address:     "EXPORT STREET",
+					BankName:    "EXPORT BANK",
+					CountryIso2: "AQ",
+					SwiftCode:   fmt.Sprintf("EXPTAQ%05d", i),
+				},
+				CountryName: "ANTARCTICA",
+			}
+		}
+		conflicts, err := suite.store.CreateBankDataBatch(ctx, entries)
+		suite.Require().NoError(err)
+		suite.Require().Empty(conflicts)
+		defer func() {
+			for _, entry := range entries {
+				suite.NoError(suite.store.DeleteBankData(ctx, entry.SwiftCode, 0))
+			}
+		}()
+
+		var exported []types.BankDataDetails
+		for _, record := range export(types.ExportFilter{CountryIso2: "AQ"}) {
+			exported = append(exported, withoutVersions(record.BankDataDetails)...)
+		}
+		suite.ElementsMatch(entries, exported)
+
+		all := export(types.ExportFilter{})
+		swiftCodes := make(map[string]struct{}, len(all))
+		for _, record := range all {
+			suite.NotContains(swiftCodes, record.SwiftCode, "SWIFT code %s exported twice", record.SwiftCode)
+			swiftCodes[record.SwiftCode] = struct{}{}
+		}
+		for _, entry := range entries {
+			suite.Contains(swiftCodes, entry.SwiftCode)
+		}
+	})
+
+	suite.Run("Callback error stops the export", func() {
+		stop := errors.New("stop")
+		calls := 0
+		err := suite.store.ExportBankData(ctx, types.ExportFilter{}, func(types.BankDataRecord) error {
+			calls++
+			return stop
+		})
+		suite.ErrorIs(err, stop)
+		suite.Equal(1, calls)
+	})
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		return suite.store.ExportBankData(ctx, types.ExportFilter{}, func(types.BankDataRecord) error {
+			return nil
+		})
+	})
+}
+
+func countryCodes(countries []types.CountryStats) []string {
+	codes := make([]string, len(countries))
+	for i, country := range countries {
+		codes[i] = country.CountryIso2
+	}
+	return codes
+}
+
+func (suite *BankDataStoreTestSuite) TestNationalBankCodes() {
+	ctx := context.Background()
+	code := StoreNationalBankCode
+
+	suite.Require().NoError(suite.store.SaveNationalBankCode(ctx, code))
+	swiftCode, err := suite.store.FindSwiftCodeByNationalBankCode(ctx, code.CountryIso2, code.NationalBankCode)
+	suite.NoError(err)
+	suite.Equal(code.SwiftCode, swiftCode)
+
+	suite.Run("Overwritten Mapping", func() {
+		remapped := code
+		remapped.SwiftCode = StoreTestBankData[1].SwiftCode
+		suite.Require().NoError(suite.store.SaveNationalBankCode(ctx, remapped))
+		swiftCode, err := suite.store.FindSwiftCodeByNationalBankCode(ctx, code.CountryIso2, code.NationalBankCode)
+		suite.NoError(err)
+		suite.Equal(remapped.SwiftCode, swiftCode)
+	})
+	suite.Run("Unknown Mapping", func() {
+		swiftCode, err := suite.store.FindSwiftCodeByNationalBankCode(ctx, "DE", code.NationalBankCode)
+		suite.NoError(err)
+		suite.Empty(swiftCode)
+	})
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		return suite.store.SaveNationalBankCode(ctx, code)
+	})
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		swiftCode, err := suite.store.FindSwiftCodeByNationalBankCode(ctx, code.CountryIso2, code.NationalBankCode)
+		suite.Empty(swiftCode)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestFindBanksDataByCountryCode() {
+	ctx := context.Background()
+	for _, countryCode := range []string{"PL", "DE", "GB"} {
+		suite.Run(countryCode, func() {
+			matches := func(swiftCode string) bool {
+				return utils.MatchesCountryCode(swiftCode, countryCode)
+			}
+			banks, err := suite.store.FindBanksDataByCountryCode(ctx, countryCode)
+			suite.NoError(err)
+			suite.assertMatchingBanks(suite.expectedBanks(matches), banks, matches)
+		})
+	}
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		banks, err := suite.store.FindBanksDataByCountryCode(ctx, "PL")
+		suite.Nil(banks)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestFindBranchesDataByHqSwiftCode() {
+	ctx := context.Background()
+	for _, hqSwiftCode := range []string{"BREXPLPWXXX", "BREXDEFFXXX", "BREXGB2LXXX"} {
+		suite.Run(hqSwiftCode, func() {
+			matches := func(swiftCode string) bool {
+				return swiftCode != hqSwiftCode && utils.MatchesBranch(swiftCode, hqSwiftCode)
+			}
+			branches, err := suite.store.FindBranchesDataByHqSwiftCode(ctx, hqSwiftCode)
+			suite.NoError(err)
+			suite.assertMatchingBanks(suite.expectedBanks(matches), branches, matches)
+		})
+	}
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		branches, err := suite.store.FindBranchesDataByHqSwiftCode(ctx, "BREXPLPWXXX")
+		suite.Nil(branches)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestFindBanksData() {
+	ctx := context.Background()
+	isHeadquarter := true
+	tests := []struct {
+		Description string
+		Filter      types.BankDataFilter
+	}{
+		{Description: "Country", Filter: types.BankDataFilter{CountryIso2: "PL"}},
+		{Description: "Bank code", Filter: types.BankDataFilter{BankCode: "BREX"}},
+		{Description: "Country and headquarters", Filter: types.BankDataFilter{CountryIso2: "DE", IsHeadquarter: &isHeadquarter}},
+		{Description: "Bank code and bank name", Filter: types.BankDataFilter{BankCode: "BREX", BankName: "bank ag"}},
+		{Description: "No matches", Filter: types.BankDataFilter{CountryIso2: "GB", BankCode: "BREX"}},
+	}
+	for _, test := range tests {
+		suite.Run(test.Description, func() {
+			var expected []types.BankDataCore
+			for _, entry := range StoreTestBankData {
+				if test.Filter.Matches(entry.BankDataCore) {
+					expected = append(expected, entry.BankDataCore)
+				}
+			}
+			banks, err := suite.store.FindBanksData(ctx, test.Filter)
+			suite.NoError(err)
+			suite.Subset(banks, expected)
+			for _, bank := range banks {
+				suite.True(test.Filter.Matches(bank), "unexpected SWIFT code %s in results", bank.SwiftCode)
+			}
+		})
+	}
+	suite.Run("Country of the SWIFT code", func() {
+		entry := StoreNewBankData
+		entry.CountryIso2 = "DE"
+		suite.Require().NoError(suite.store.SaveBankData(ctx, entry))
+		defer suite.store.DeleteBankData(ctx, entry.SwiftCode, 0)
+		bankCode := entry.SwiftCode[:utils.BankCodeLength]
+
+		for _, filter := range []types.BankDataFilter{{CountryIso2: "PL"}, {CountryIso2: "PL", BankCode: bankCode}} {
+			banks, err := suite.store.FindBanksData(ctx, filter)
+			suite.NoError(err)
+			suite.Contains(banks, entry.BankDataCore)
+		}
+		for _, filter := range []types.BankDataFilter{{CountryIso2: "DE"}, {CountryIso2: "DE", BankCode: bankCode}} {
+			banks, err := suite.store.FindBanksData(ctx, filter)
+			suite.NoError(err)
+			suite.NotContains(banks, entry.BankDataCore)
+		}
+	})
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		banks, err := suite.store.FindBanksData(ctx, types.BankDataFilter{CountryIso2: "PL"})
+		suite.Nil(banks)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestSearchBankData() {
+	ctx := context.Background()
+	searchCodes := func(terms []string) []string {
+		results, err := suite.store.SearchBankData(ctx, terms, utils.SearchMaxLimit)
+		suite.NoError(err)
+		var swiftCodes []string
+		for _, result := range results {
+			swiftCodes = append(swiftCodes, result.SwiftCode)
+		}
+		return swiftCodes
+	}
+
+	suite.Run("Address match", func() {
+		results, err := suite.store.SearchBankData(ctx, []string{"TAUNUSANLAGE"}, utils.SearchMaxLimit)
+		suite.NoError(err)
+		suite.Equal([]types.SearchResult{{BankDataCore: StoreTestBankData[3].BankDataCore, Score: utils.SearchWeightAddress}}, results)
+	})
+
+	suite.Run("Results are ranked by score", func() {
+		results, err := suite.store.SearchBankData(ctx, []string{"BREX", "BERLIN"}, utils.SearchMaxLimit)
+		suite.NoError(err)
+		suite.Require().NotEmpty(results)
+		suite.Equal(StoreTestBankData[4].SwiftCode, results[0].SwiftCode)
+		suite.Equal(utils.SearchWeightBankName+utils.SearchWeightAddress, results[0].Score)
+		for i := 1; i < len(results); i++ {
+			suite.GreaterOrEqual(results[i-1].Score, results[i].Score)
+		}
+	})
+
+	suite.Run("Limit", func() {
+		results, err := suite.store.SearchBankData(ctx, []string{"BREX"}, 2)
+		suite.NoError(err)
+		suite.Len(results, 2)
+	})
+
+	suite.Run("No matches", func() {
+		suite.Empty(searchCodes([]string{"NONEXISTENT"}))
+	})
+
+	suite.Run("Index follows updates", func() {
+		existing := StoreTestBankData[0]
+		updatedData := existing
+		updatedData.Address = "ZIELONA 7 WARSZAWA"
+
+		suite.NoError(suite.store.SaveBankData(ctx, updatedData))
+		suite.Contains(searchCodes([]string{"ZIELONA"}), existing.SwiftCode)
+		suite.NotContains(searchCodes([]string{"PROSTA"}), existing.SwiftCode)
+
+		suite.NoError(suite.store.SaveBankData(ctx, existing))
+		suite.NotContains(searchCodes([]string{"ZIELONA"}), existing.SwiftCode)
+		suite.Contains(searchCodes([]string{"PROSTA"}), existing.SwiftCode)
+	})
+
+	suite.Run("Index follows deletes", func() {
+		entry := StoreNewBankData
+
+		suite.NoError(suite.store.SaveBankData(ctx, entry))
+		suite.Contains(searchCodes([]string{"LODZKA"}), entry.SwiftCode)
+
+		suite.NoError(suite.store.DeleteBankData(ctx, entry.SwiftCode, 0))
+		suite.NotContains(searchCodes([]string{"LODZKA"}), entry.SwiftCode)
+	})
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		results, err := suite.store.SearchBankData(ctx, []string{"BREX"}, utils.SearchDefaultLimit)
+		suite.Nil(results)
+		return err
+	})
+}
+
+func (suite *BankDataStoreTestSuite) TestAutocompleteBankData() {
+	ctx := context.Background()
+	autocompleteCodes := func(prefix string, limit int) []string {
+		banks, err := suite.store.AutocompleteBankData(ctx, prefix, limit)
+		suite.NoError(err)
+		var swiftCodes []string
+		for _, bank := range banks {
+			swiftCodes = append(swiftCodes, bank.SwiftCode)
+		}
+		return swiftCodes
+	}
+
+	suite.Run("Headquarters first", func() {
+		expected := []string{"BREXDEFFXXX", "BREXPLPWXXX", "BREXDEFFBER", "BREXPLPWGDA", "BREXPLPWKRK"}
+		suite.Equal(expected, autocompleteCodes("BREX", utils.AutocompleteMaxLimit))
+	})
+
+	suite.Run("Limit", func() {
+		suite.Equal([]string{"BREXDEFFXXX", "BREXPLPWXXX", "BREXDEFFBER"}, autocompleteCodes("BREX", 3))
+	})
+
+	suite.Run("Bank name prefix", func() {
+		banks, err := suite.store.AutocompleteBankData(ctx, "BREX BANK A", utils.AutocompleteMaxLimit)
+		suite.NoError(err)
+		suite.Equal([]types.BankDataCore{StoreTestBankData[3].BankDataCore, StoreTestBankData[4].BankDataCore}, banks)
+	})
+
+	suite.Run("SWIFT code prefix", func() {
+		suite.Equal([]string{"BREXPLPWKRK"}, autocompleteCodes("BREXPLPWK", utils.AutocompleteMaxLimit))
+	})
+
+	suite.Run("No matches", func() {
+		suite.Empty(autocompleteCodes("BREXX", utils.AutocompleteMaxLimit))
+	})
+
+	suite.Run("Index follows updates", func() {
+		existing := StoreTestBankData[4]
+		updatedData := existing
+		updatedData.BankName = "ZETA BANK"
+
+		suite.NoError(suite.store.SaveBankData(ctx, updatedData))
+		suite.Equal([]string{existing.SwiftCode}, autocompleteCodes("ZETA", utils.AutocompleteMaxLimit))
+		suite.Equal([]string{"BREXDEFFXXX"}, autocompleteCodes("BREX BANK A", utils.AutocompleteMaxLimit))
+
+		suite.NoError(suite.store.SaveBankData(ctx, existing))
+		suite.Empty(autocompleteCodes("ZETA", utils.AutocompleteMaxLimit))
+	})
+
+	suite.Run("Index follows deletes", func() {
+		entry := StoreNewBankData
+
+		suite.NoError(suite.store.SaveBankData(ctx, entry))
+		suite.Equal([]string{entry.SwiftCode}, autocompleteCodes("BREXPLPWL", utils.AutocompleteMaxLimit))
+
+		suite.NoError(suite.store.DeleteBankData(ctx, entry.SwiftCode, 0))
+		suite.Empty(autocompleteCodes("BREXPLPWL", utils.AutocompleteMaxLimit))
+	})
+
+	suite.assertCanceledContext(func(ctx context.Context) error {
+		banks, err := suite.store.AutocompleteBankData(ctx, "BREX", utils.AutocompleteDefaultLimit)
+		suite.Nil(banks)
+		return err
+	})
+}
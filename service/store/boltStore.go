@@ -0,0 +1,605 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+	"go.etcd.io/bbolt"
+)
+
+var (
+	boltBankData     = []byte(utils.BoltBucketBankData)
+	boltCountryIndex = []byte(utils.BoltBucketCountryIndex)
+	boltSearchIndex  = []byte(utils.BoltBucketSearchIndex)
+	boltAutocomplete = []byte(utils.BoltBucketAutocomplete)
+	boltBankCodes    = []byte(utils.BoltBucketBankCodes)
+)
+
+// BoltStore keeps bank data as JSON, together with its version and modification time, in a bbolt file. Keys are sorted, so BIC8 lookups are prefix scans
+// over the bank data bucket, while country lookups use a separate index bucket keyed by country code + SWIFT code.
+// The search index bucket is keyed by search term + NUL + SWIFT code and holds the term weight,
+// and the autocomplete bucket is keyed by the entries described at utils.AutocompleteMember.
+// National bank codes are kept in their own bucket, keyed by country code + national bank code.
+type BoltStore struct {
+	db *bbolt.DB
+}
+
+func (s *BoltStore) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(boltBankData) == nil {
+			return fmt.Errorf("bucket %s does not exist", boltBankData)
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) DoesSwiftCodeExist(ctx context.Context, swiftCode string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return utils.SwiftCodeExistsError, err
+	}
+	var exists int64
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(boltBankData).Get([]byte(swiftCode)) != nil {
+			exists = 1
+		}
+		return nil
+	})
+	if err != nil {
+		return utils.SwiftCodeExistsError, err
+	}
+	return exists, nil
+}
+
+func (s *BoltStore) SaveBankData(ctx context.Context, data types.BankDataDetails) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := s.db.Batch(func(tx *bbolt.Tx) error {
+		return putBankData(tx, data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store data for key %s: %w", data.SwiftCode, err)
+	}
+	return nil
+}
+
+func (s *BoltStore) CreateBankData(ctx context.Context, data types.BankDataDetails) (bool, error) {
+	created, err := s.saveBankDataIf(ctx, data, 0, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to create data for key %s: %w", data.SwiftCode, err)
+	}
+	return created, nil
+}
+
+func (s *BoltStore) CreateBankDataBatch(ctx context.Context, data []types.BankDataDetails) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(boltBankData)
+		for _, entry := range data {
+			if bucket.Get([]byte(entry.SwiftCode)) != nil {
+				conflicts = append(conflicts, entry.SwiftCode)
+			}
+		}
+		if len(conflicts) > 0 {
+			return nil
+		}
+		for _, entry := range data {
+			if err := putBankData(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch of %d entries: %w", len(data), err)
+	}
+	return conflicts, nil
+}
+
+func (s *BoltStore) UpdateBankData(ctx context.Context, data types.BankDataDetails, expectedVersion int64) (bool, error) {
+	updated, err := s.saveBankDataIf(ctx, data, expectedVersion, true)
+	if err != nil {
+		return false, fmt.Errorf("failed to update data for key %s: %w", data.SwiftCode, err)
+	}
+	return updated, nil
+}
+
+func (s *BoltStore) saveBankDataIf(ctx context.Context, data types.BankDataDetails, expectedVersion int64, shouldExist bool) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	saved := false
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		current, err := storedBankVersion(tx, data.SwiftCode)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(data.SwiftCode, current, expectedVersion); err != nil {
+			return err
+		}
+		if exists := current != 0; exists != shouldExist {
+			return nil
+		}
+		saved = true
+		return putBankData(tx, data)
+	})
+	if err != nil {
+		return false, err
+	}
+	return saved, nil
+}
+
+func (s *BoltStore) DeleteBankData(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		current, err := storedBankVersion(tx, swiftCode)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(swiftCode, current, expectedVersion); err != nil {
+			return err
+		}
+		if err := removeTermIndexes(tx, swiftCode); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBankData).Delete([]byte(swiftCode)); err != nil {
+			return err
+		}
+		return tx.Bucket(boltCountryIndex).Delete(boltCountryIndexKey(swiftCode))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete data for SWIFT code %s: %w", swiftCode, err)
+	}
+	return nil
+}
+
+func (s *BoltStore) FindBanksDataByCountryCode(ctx context.Context, countryCode string) ([]types.BankDataCore, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var banks []types.BankDataCore
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bankData := tx.Bucket(boltBankData)
+		prefix := []byte(countryCode)
+		cursor := tx.Bucket(boltCountryIndex).Cursor()
+		for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+			bank, err := decodeBankData(bankData.Get(key[len(prefix):]))
+			if err != nil {
+				return err
+			}
+			if bank != nil {
+				banks = append(banks, bank.BankDataCore)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch banks for country code %s: %w", countryCode, err)
+	}
+	return banks, nil
+}
+
+// FindBanksData scans the bank data by its bank code prefix when the filter sets one,
+// and falls back to the country index otherwise.
+func (s *BoltStore) FindBanksData(ctx context.Context, filter types.BankDataFilter) ([]types.BankDataCore, error) {
+	if filter.BankCode == "" && filter.CountryIso2 != "" {
+		banks, err := s.FindBanksDataByCountryCode(ctx, filter.CountryIso2)
+		if err != nil {
+			return nil, err
+		}
+		return filterBanksData(banks, filter), nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var banks []types.BankDataCore
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		prefix := []byte(filter.BankCode)
+		cursor := tx.Bucket(boltBankData).Cursor()
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			bank, err := decodeBankData(value)
+			if err != nil {
+				return err
+			}
+			if filter.Matches(bank.BankDataCore) {
+				banks = append(banks, bank.BankDataCore)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch banks for filter: %w", err)
+	}
+	return banks, nil
+}
+
+func (s *BoltStore) SearchBankData(ctx context.Context, terms []string, limit int) ([]types.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var results []types.SearchResult
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		scores := make(map[string]int)
+		cursor := tx.Bucket(boltSearchIndex).Cursor()
+		for _, term := range terms {
+			prefix := boltSearchIndexKey(term, "")
+			for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+				weight, err := strconv.Atoi(string(value))
+				if err != nil {
+					return err
+				}
+				scores[string(key[len(prefix):])] += weight
+			}
+		}
+		ranked := rankSearchScores(scores, limit)
+		bankData := tx.Bucket(boltBankData)
+		banks := make([]types.BankDataCore, 0, len(ranked))
+		for _, score := range ranked {
+			bank, err := decodeBankData(bankData.Get([]byte(score.swiftCode)))
+			if err != nil {
+				return err
+			}
+			if bank != nil {
+				banks = append(banks, bank.BankDataCore)
+			}
+		}
+		results = searchResults(ranked, banks)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search banks: %w", err)
+	}
+	return results, nil
+}
+
+func (s *BoltStore) AutocompleteBankData(ctx context.Context, prefix string, limit int) ([]types.BankDataCore, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var banks []types.BankDataCore
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var members []string
+		cursor := tx.Bucket(boltAutocomplete).Cursor()
+		for _, group := range []string{utils.AutocompleteGroupHq, utils.AutocompleteGroupBranch} {
+			groupPrefix := []byte(group + prefix)
+			for key, _ := cursor.Seek(groupPrefix); key != nil && bytes.HasPrefix(key, groupPrefix) && len(members) < 2*limit; key, _ = cursor.Next() {
+				members = append(members, string(key))
+			}
+		}
+		bankData := tx.Bucket(boltBankData)
+		for _, swiftCode := range autocompleteSwiftCodes(members, limit) {
+			bank, err := decodeBankData(bankData.Get([]byte(swiftCode)))
+			if err != nil {
+				return err
+			}
+			if bank != nil {
+				banks = append(banks, bank.BankDataCore)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to autocomplete prefix %s: %w", prefix, err)
+	}
+	return banks, nil
+}
+
+// FindCountriesStats counts the entries of the country index, whose keys end with the SWIFT code.
+func (s *BoltStore) FindCountriesStats(ctx context.Context) ([]types.CountryStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var swiftCodes []string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(boltCountryIndex).ForEach(func(key, _ []byte) error {
+			swiftCodes = append(swiftCodes, string(key[utils.CountryCodeLength:]))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count banks by country: %w", err)
+	}
+	return countCountryStats(swiftCodes), nil
+}
+
+// ExportBankData reads the bank data bucket - or the country index, if the filter sets a country - in pages of
+// utils.ExportBatchSize keys, each in its own read transaction, so fn never runs inside a transaction.
+func (s *BoltStore) ExportBankData(ctx context.Context, filter types.ExportFilter, fn func(types.BankDataRecord) error) error {
+	bucket, prefix := boltBankData, []byte(nil)
+	if filter.CountryIso2 != "" {
+		bucket, prefix = boltCountryIndex, []byte(filter.CountryIso2)
+	}
+
+	var after []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var records []types.BankDataRecord
+		keys := 0
+		err := s.db.View(func(tx *bbolt.Tx) error {
+			bankData := tx.Bucket(boltBankData)
+			cursor := tx.Bucket(bucket).Cursor()
+			key, _ := cursor.Seek(prefix)
+			if after != nil {
+				if key, _ = cursor.Seek(after); bytes.Equal(key, after) {
+					key, _ = cursor.Next()
+				}
+			}
+			var last []byte
+			for ; key != nil && bytes.HasPrefix(key, prefix) && keys < utils.ExportBatchSize; key, _ = cursor.Next() {
+				keys++
+				last = key
+				record, err := decodeBankRecord(bankData.Get(key[len(prefix):]))
+				if err != nil {
+					return err
+				}
+				if record != nil {
+					records = append(records, *record)
+				}
+			}
+			// The keys are only valid within the transaction.
+			after = bytes.Clone(last)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to export bank data: %w", err)
+		}
+
+		for _, record := range records {
+			if !filter.Matches(record) {
+				continue
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if keys < utils.ExportBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *BoltStore) SaveNationalBankCode(ctx context.Context, code types.NationalBankCode) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := s.db.Batch(func(tx *bbolt.Tx) error {
+		return tx.Bucket(boltBankCodes).Put([]byte(code.CountryIso2+code.NationalBankCode), []byte(code.SwiftCode))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store national bank code %s of %s: %w", code.NationalBankCode, code.CountryIso2, err)
+	}
+	return nil
+}
+
+func (s *BoltStore) FindSwiftCodeByNationalBankCode(ctx context.Context, countryIso2 string, nationalBankCode string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	var swiftCode string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		swiftCode = string(tx.Bucket(boltBankCodes).Get([]byte(countryIso2 + nationalBankCode)))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch national bank code %s of %s: %w", nationalBankCode, countryIso2, err)
+	}
+	return swiftCode, nil
+}
+
+// RebuildIndexes recreates the country, search and autocomplete index buckets from the stored bank data in a single transaction.
+func (s *BoltStore) RebuildIndexes(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	count := 0
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{boltCountryIndex, boltSearchIndex, boltAutocomplete} {
+			if err := tx.DeleteBucket(name); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+				return err
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(boltBankData).ForEach(func(key, value []byte) error {
+			bank, err := decodeBankData(value)
+			if err != nil {
+				return err
+			}
+			count++
+			return putBankIndexes(tx, *bank)
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to rebuild indexes: %w", err)
+	}
+	return count, nil
+}
+
+func (s *BoltStore) FindBranchesDataByHqSwiftCode(ctx context.Context, swiftCode string) ([]types.BankDataCore, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var banks []types.BankDataCore
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		prefix := []byte(swiftCode[:utils.SwiftCodeLength])
+		cursor := tx.Bucket(boltBankData).Cursor()
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			if string(key) == swiftCode || !utils.MatchesBranch(string(key), swiftCode) {
+				continue
+			}
+			bank, err := decodeBankData(value)
+			if err != nil {
+				return err
+			}
+			banks = append(banks, bank.BankDataCore)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch branches for SWIFT code %s: %w", swiftCode, err)
+	}
+	return banks, nil
+}
+
+func (s *BoltStore) FindBankDetailsBySwiftCode(ctx context.Context, swiftCode string) (*types.BankDataDetails, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var bank *types.BankDataDetails
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		bank, err = decodeBankData(tx.Bucket(boltBankData).Get([]byte(swiftCode)))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data from bolt for key %s: %w", swiftCode, err)
+	}
+	return bank, nil
+}
+
+func (s *BoltStore) FindBanksDetailsBySwiftCodes(ctx context.Context, swiftCodes []string) ([]types.BankDataDetails, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var banks []types.BankDataDetails
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bankData := tx.Bucket(boltBankData)
+		for _, swiftCode := range swiftCodes {
+			bank, err := decodeBankData(bankData.Get([]byte(swiftCode)))
+			if err != nil {
+				return err
+			}
+			if bank != nil {
+				banks = append(banks, *bank)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data for %d SWIFT codes: %w", len(swiftCodes), err)
+	}
+	return banks, nil
+}
+
+// putBankData stores the data at the version following the stored one and replaces its index entries.
+func putBankData(tx *bbolt.Tx, data types.BankDataDetails) error {
+	current, err := storedBankVersion(tx, data.SwiftCode)
+	if err != nil {
+		return err
+	}
+	data.Version = current + 1
+	value, err := encodeBankData(data)
+	if err != nil {
+		return err
+	}
+	if err := removeTermIndexes(tx, data.SwiftCode); err != nil {
+		return err
+	}
+	if err := tx.Bucket(boltBankData).Put([]byte(data.SwiftCode), value); err != nil {
+		return err
+	}
+	return putBankIndexes(tx, data)
+}
+
+func putBankIndexes(tx *bbolt.Tx, data types.BankDataDetails) error {
+	if err := tx.Bucket(boltCountryIndex).Put(boltCountryIndexKey(data.SwiftCode), nil); err != nil {
+		return err
+	}
+	searchIndex := tx.Bucket(boltSearchIndex)
+	for term, weight := range utils.SearchTermWeights(data.BankName, data.Address) {
+		if err := searchIndex.Put(boltSearchIndexKey(term, data.SwiftCode), []byte(strconv.Itoa(weight))); err != nil {
+			return err
+		}
+	}
+	autocomplete := tx.Bucket(boltAutocomplete)
+	for _, term := range utils.AutocompleteTerms(data.SwiftCode, data.BankName) {
+		if err := autocomplete.Put([]byte(utils.AutocompleteMember(term, data.SwiftCode, data.IsHeadquarter)), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeTermIndexes deletes the search and autocomplete index entries of the currently stored version of the bank data.
+func removeTermIndexes(tx *bbolt.Tx, swiftCode string) error {
+	old, err := decodeBankData(tx.Bucket(boltBankData).Get([]byte(swiftCode)))
+	if err != nil || old == nil {
+		return err
+	}
+	searchIndex := tx.Bucket(boltSearchIndex)
+	for term := range utils.SearchTermWeights(old.BankName, old.Address) {
+		if err := searchIndex.Delete(boltSearchIndexKey(term, swiftCode)); err != nil {
+			return err
+		}
+	}
+	autocomplete := tx.Bucket(boltAutocomplete)
+	for _, term := range utils.AutocompleteTerms(swiftCode, old.BankName) {
+		if err := autocomplete.Delete([]byte(utils.AutocompleteMember(term, swiftCode, old.IsHeadquarter))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storedBankVersion returns the version of the stored bank data, or 0 if there is none.
+func storedBankVersion(tx *bbolt.Tx, swiftCode string) (int64, error) {
+	bank, err := decodeBankData(tx.Bucket(boltBankData).Get([]byte(swiftCode)))
+	if err != nil || bank == nil {
+		return 0, err
+	}
+	return bank.Version, nil
+}
+
+func boltSearchIndexKey(term string, swiftCode string) []byte {
+	return []byte(term + "\x00" + swiftCode)
+}
+
+func boltCountryIndexKey(swiftCode string) []byte {
+	return []byte(swiftCode[utils.CountryCodeOffset:utils.CountryCodeOffset+utils.CountryCodeLength] + swiftCode)
+}
+
+// encodeBankData stores the bank data as a types.BankDataRecord modified now.
+func encodeBankData(data types.BankDataDetails) ([]byte, error) {
+	updatedAt := time.Now().UTC()
+	return json.Marshal(types.BankDataRecord{BankDataDetails: data, UpdatedAt: &updatedAt})
+}
+
+func decodeBankRecord(value []byte) (*types.BankDataRecord, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var record types.BankDataRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func decodeBankData(value []byte) (*types.BankDataDetails, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var bank types.BankDataDetails
+	if err := json.Unmarshal(value, &bank); err != nil {
+		return nil, err
+	}
+	return &bank, nil
+}
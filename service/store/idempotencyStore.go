@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisIdempotencyStore keeps the idempotency records as JSON strings, which expire after the TTL.
+type RedisIdempotencyStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// ReserveIdempotencyKey claims the key with SET NX. A key which expires between the SET NX and the GET of the stored record
+// is claimed again.
+func (s *RedisIdempotencyStore) ReserveIdempotencyKey(ctx context.Context, key string, record types.IdempotencyRecord) (*types.IdempotencyRecord, error) {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode idempotency record %s: %w", key, err)
+	}
+	for i := 0; i < utils.RedisTxMaxRetries; i++ {
+		reserved, err := s.client.SetNX(ctx, utils.IdempotencyKey(key), value, s.ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key %s: %w", key, err)
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		stored, err := s.client.Get(ctx, utils.IdempotencyKey(key)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch idempotency key %s: %w", key, err)
+		}
+		var existing types.IdempotencyRecord
+		if err := json.Unmarshal(stored, &existing); err != nil {
+			return nil, fmt.Errorf("failed to decode idempotency record %s: %w", key, err)
+		}
+		return &existing, nil
+	}
+	return nil, fmt.Errorf("failed to reserve idempotency key %s: too many concurrent modifications", key)
+}
+
+// SaveIdempotencyRecord replaces the reservation, restarting its TTL.
+func (s *RedisIdempotencyStore) SaveIdempotencyRecord(ctx context.Context, key string, record types.IdempotencyRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency record %s: %w", key, err)
+	}
+	if err := s.client.Set(ctx, utils.IdempotencyKey(key), value, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotency record %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *RedisIdempotencyStore) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, utils.IdempotencyKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to delete idempotency key %s: %w", key, err)
+	}
+	return nil
+}
+
+// MemoryIdempotencyStore keeps the idempotency records of a single instance, for the backends without Redis.
+// Expired records are swept on reservations, at most once per TTL.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	records   map[string]memoryIdempotencyRecord
+	nextSweep time.Time
+}
+
+type memoryIdempotencyRecord struct {
+	record    types.IdempotencyRecord
+	expiresAt time.Time
+}
+
+func (s *MemoryIdempotencyStore) ReserveIdempotencyKey(ctx context.Context, key string, record types.IdempotencyRecord) (*types.IdempotencyRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextSweep) {
+		for storedKey, stored := range s.records {
+			if !now.Before(stored.expiresAt) {
+				delete(s.records, storedKey)
+			}
+		}
+		s.nextSweep = now.Add(s.ttl)
+	}
+	if stored, ok := s.records[key]; ok && now.Before(stored.expiresAt) {
+		return &stored.record, nil
+	}
+	s.records[key] = memoryIdempotencyRecord{record: record, expiresAt: now.Add(s.ttl)}
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) SaveIdempotencyRecord(ctx context.Context, key string, record types.IdempotencyRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = memoryIdempotencyRecord{record: record, expiresAt: time.Now().Add(s.ttl)}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
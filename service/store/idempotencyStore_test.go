@@ -0,0 +1,112 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DroppedHard/SWIFT-service/config"
+	"github.com/DroppedHard/SWIFT-service/db"
+	"github.com/DroppedHard/SWIFT-service/service/store"
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+// IdempotencyStoreTestSuite holds behavioural tests shared by every types.IdempotencyStore implementation.
+type IdempotencyStoreTestSuite struct {
+	suite.Suite
+	store      types.IdempotencyStore
+	setupStore func() types.IdempotencyStore
+	closeStore func()
+}
+
+func TestMemoryIdempotencyStoreSuite(t *testing.T) {
+	suite.Run(t, &IdempotencyStoreTestSuite{
+		setupStore: func() types.IdempotencyStore { return store.NewMemoryIdempotencyStore(time.Hour) },
+	})
+}
+
+func TestRedisIdempotencyStoreSuite(t *testing.T) {
+	var rdb *redis.Client
+	suite.Run(t, &IdempotencyStoreTestSuite{
+		setupStore: func() types.IdempotencyStore {
+			rdb = db.NewRedisStorage(&redis.Options{
+				Addr:     fmt.Sprintf("%s:%s", config.Envs.DBHost, config.Envs.DBPort),
+				Password: config.Envs.DBPassword,
+				DB:       config.Envs.DBTestNum,
+			})
+			return store.NewRedisIdempotencyStore(rdb, time.Hour)
+		},
+		closeStore: func() { rdb.Close() },
+	})
+}
+
+func TestMemoryIdempotencyStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	idempotencyStore := store.NewMemoryIdempotencyStore(10 * time.Millisecond)
+	record := types.IdempotencyRecord{RequestHash: "hash"}
+
+	stored, err := idempotencyStore.ReserveIdempotencyKey(ctx, "expiring", record)
+	if err != nil || stored != nil {
+		t.Fatalf("expected a free key, got %v, %v", stored, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	stored, err = idempotencyStore.ReserveIdempotencyKey(ctx, "expiring", record)
+	if err != nil || stored != nil {
+		t.Fatalf("expected the expired key to be free, got %v, %v", stored, err)
+	}
+}
+
+func (suite *IdempotencyStoreTestSuite) SetupSuite() {
+	suite.store = suite.setupStore()
+}
+
+func (suite *IdempotencyStoreTestSuite) TearDownSuite() {
+	if suite.closeStore != nil {
+		suite.closeStore()
+	}
+}
+
+func (suite *IdempotencyStoreTestSuite) TestIdempotencyKeyLifecycle() {
+	ctx := context.Background()
+	key := fmt.Sprintf("store-test-%d", time.Now().UnixNano())
+	reservation := types.IdempotencyRecord{RequestHash: "hash"}
+	defer suite.store.DeleteIdempotencyKey(ctx, key)
+
+	stored, err := suite.store.ReserveIdempotencyKey(ctx, key, reservation)
+	suite.NoError(err)
+	suite.Nil(stored)
+
+	stored, err = suite.store.ReserveIdempotencyKey(ctx, key, types.IdempotencyRecord{RequestHash: "other hash"})
+	suite.NoError(err)
+	suite.Equal(&reservation, stored)
+
+	record := types.IdempotencyRecord{
+		RequestHash: "hash",
+		Status:      201,
+		Header:      map[string][]string{"Content-Type": {"application/json"}},
+		Body:        []byte(`{"message":"created"}`),
+	}
+	suite.NoError(suite.store.SaveIdempotencyRecord(ctx, key, record))
+	stored, err = suite.store.ReserveIdempotencyKey(ctx, key, reservation)
+	suite.NoError(err)
+	suite.Equal(&record, stored)
+
+	suite.NoError(suite.store.DeleteIdempotencyKey(ctx, key))
+	stored, err = suite.store.ReserveIdempotencyKey(ctx, key, reservation)
+	suite.NoError(err)
+	suite.Nil(stored)
+}
+
+func (suite *IdempotencyStoreTestSuite) TestCanceledContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := suite.store.ReserveIdempotencyKey(ctx, "canceled", types.IdempotencyRecord{})
+	suite.ErrorContains(err, "context canceled")
+	suite.ErrorContains(suite.store.SaveIdempotencyRecord(ctx, "canceled", types.IdempotencyRecord{}), "context canceled")
+	suite.ErrorContains(suite.store.DeleteIdempotencyKey(ctx, "canceled"), "context canceled")
+}
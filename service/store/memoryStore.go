@@ -0,0 +1,353 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+)
+
+type MemoryStore struct {
+	mu                sync.RWMutex
+	banks             map[string]types.BankDataDetails
+	updatedAt         map[string]time.Time
+	terms             map[string]map[string]int
+	autocomplete      []string
+	nationalBankCodes map[string]string
+}
+
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (s *MemoryStore) DoesSwiftCodeExist(ctx context.Context, swiftCode string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return utils.SwiftCodeExistsError, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.banks[swiftCode]; ok {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (s *MemoryStore) SaveBankData(ctx context.Context, data types.BankDataDetails) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putBank(data)
+	return nil
+}
+
+func (s *MemoryStore) CreateBankData(ctx context.Context, data types.BankDataDetails) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.banks[data.SwiftCode]; ok {
+		return false, nil
+	}
+	s.putBank(data)
+	return true, nil
+}
+
+func (s *MemoryStore) CreateBankDataBatch(ctx context.Context, data []types.BankDataDetails) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var conflicts []string
+	for _, entry := range data {
+		if _, ok := s.banks[entry.SwiftCode]; ok {
+			conflicts = append(conflicts, entry.SwiftCode)
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+	for _, entry := range data {
+		s.putBank(entry)
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) UpdateBankData(ctx context.Context, data types.BankDataDetails, expectedVersion int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkVersion(data.SwiftCode, s.banks[data.SwiftCode].Version, expectedVersion); err != nil {
+		return false, err
+	}
+	if _, ok := s.banks[data.SwiftCode]; !ok {
+		return false, nil
+	}
+	s.putBank(data)
+	return true, nil
+}
+
+func (s *MemoryStore) DeleteBankData(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkVersion(swiftCode, s.banks[swiftCode].Version, expectedVersion); err != nil {
+		return err
+	}
+	s.removeBank(swiftCode)
+	return nil
+}
+
+func (s *MemoryStore) FindBanksDataByCountryCode(ctx context.Context, countryCode string) ([]types.BankDataCore, error) {
+	return s.findBanksDataMatching(ctx, func(swiftCode string) bool {
+		return utils.MatchesCountryCode(swiftCode, countryCode)
+	})
+}
+
+func (s *MemoryStore) FindBanksData(ctx context.Context, filter types.BankDataFilter) ([]types.BankDataCore, error) {
+	banks, err := s.findBanksDataMatching(ctx, func(swiftCode string) bool {
+		return (filter.CountryIso2 == "" || utils.MatchesCountryCode(swiftCode, filter.CountryIso2)) &&
+			(filter.BankCode == "" || strings.HasPrefix(swiftCode, filter.BankCode))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filterBanksData(banks, filter), nil
+}
+
+func (s *MemoryStore) FindBranchesDataByHqSwiftCode(ctx context.Context, swiftCode string) ([]types.BankDataCore, error) {
+	return s.findBanksDataMatching(ctx, func(branchCode string) bool {
+		return branchCode != swiftCode && utils.MatchesBranch(branchCode, swiftCode)
+	})
+}
+
+func (s *MemoryStore) FindBankDetailsBySwiftCode(ctx context.Context, swiftCode string) (*types.BankDataDetails, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bank, ok := s.banks[swiftCode]
+	if !ok {
+		return nil, nil
+	}
+	return &bank, nil
+}
+
+func (s *MemoryStore) SearchBankData(ctx context.Context, terms []string, limit int) ([]types.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scores := make(map[string]int)
+	for _, term := range terms {
+		for swiftCode, weight := range s.terms[term] {
+			scores[swiftCode] += weight
+		}
+	}
+	ranked := rankSearchScores(scores, limit)
+	banks := make([]types.BankDataCore, 0, len(ranked))
+	for _, score := range ranked {
+		banks = append(banks, s.banks[score.swiftCode].BankDataCore)
+	}
+	return searchResults(ranked, banks), nil
+}
+
+func (s *MemoryStore) AutocompleteBankData(ctx context.Context, prefix string, limit int) ([]types.BankDataCore, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Like ZRANGEBYLEX on Redis, every group is read from its first entry starting with the prefix, up to 2*limit entries.
+	var members []string
+	for _, group := range []string{utils.AutocompleteGroupHq, utils.AutocompleteGroupBranch} {
+		start := sort.SearchStrings(s.autocomplete, group+prefix)
+		end := sort.SearchStrings(s.autocomplete, group+prefix+autocompleteRangeEnd)
+		members = append(members, s.autocomplete[start:min(end, start+2*limit)]...)
+	}
+	swiftCodes := autocompleteSwiftCodes(members, limit)
+	banks := make([]types.BankDataCore, len(swiftCodes))
+	for i, swiftCode := range swiftCodes {
+		banks[i] = s.banks[swiftCode].BankDataCore
+	}
+	return banks, nil
+}
+
+func (s *MemoryStore) FindCountriesStats(ctx context.Context) ([]types.CountryStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	swiftCodes := make([]string, 0, len(s.banks))
+	for swiftCode := range s.banks {
+		swiftCodes = append(swiftCodes, swiftCode)
+	}
+	return countCountryStats(swiftCodes), nil
+}
+
+// ExportBankData copies the records in batches of utils.ExportBatchSize, so the lock is not held while fn consumes them.
+func (s *MemoryStore) ExportBankData(ctx context.Context, filter types.ExportFilter, fn func(types.BankDataRecord) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	var swiftCodes []string
+	for swiftCode := range s.banks {
+		if filter.CountryIso2 == "" || utils.MatchesCountryCode(swiftCode, filter.CountryIso2) {
+			swiftCodes = append(swiftCodes, swiftCode)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Strings(swiftCodes)
+
+	for start := 0; start < len(swiftCodes); start += utils.ExportBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, record := range s.bankRecords(swiftCodes[start:min(start+utils.ExportBatchSize, len(swiftCodes))]) {
+			if !filter.Matches(record) {
+				continue
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bankRecords returns the records of the SWIFT codes which are still stored.
+func (s *MemoryStore) bankRecords(swiftCodes []string) []types.BankDataRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]types.BankDataRecord, 0, len(swiftCodes))
+	for _, swiftCode := range swiftCodes {
+		if bank, ok := s.banks[swiftCode]; ok {
+			updatedAt := s.updatedAt[swiftCode]
+			records = append(records, types.BankDataRecord{BankDataDetails: bank, UpdatedAt: &updatedAt})
+		}
+	}
+	return records
+}
+
+func (s *MemoryStore) SaveNationalBankCode(ctx context.Context, code types.NationalBankCode) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nationalBankCodes[code.CountryIso2+code.NationalBankCode] = code.SwiftCode
+	return nil
+}
+
+func (s *MemoryStore) FindSwiftCodeByNationalBankCode(ctx context.Context, countryIso2 string, nationalBankCode string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.nationalBankCodes[countryIso2+nationalBankCode], nil
+}
+
+// putBank stores the bank data at the next version and replaces its search and autocomplete terms. The caller has to hold the write lock.
+func (s *MemoryStore) putBank(data types.BankDataDetails) {
+	data.Version = s.banks[data.SwiftCode].Version + 1
+	s.removeBank(data.SwiftCode)
+	s.banks[data.SwiftCode] = data
+	s.updatedAt[data.SwiftCode] = time.Now().UTC()
+	for term, weight := range utils.SearchTermWeights(data.BankName, data.Address) {
+		if s.terms[term] == nil {
+			s.terms[term] = make(map[string]int)
+		}
+		s.terms[term][data.SwiftCode] = weight
+	}
+	for _, term := range utils.AutocompleteTerms(data.SwiftCode, data.BankName) {
+		member := utils.AutocompleteMember(term, data.SwiftCode, data.IsHeadquarter)
+		if i, found := slices.BinarySearch(s.autocomplete, member); !found {
+			s.autocomplete = slices.Insert(s.autocomplete, i, member)
+		}
+	}
+}
+
+// removeBank deletes the bank data together with its search and autocomplete terms. The caller has to hold the write lock.
+func (s *MemoryStore) removeBank(swiftCode string) {
+	bank, ok := s.banks[swiftCode]
+	if !ok {
+		return
+	}
+	for term := range utils.SearchTermWeights(bank.BankName, bank.Address) {
+		delete(s.terms[term], swiftCode)
+		if len(s.terms[term]) == 0 {
+			delete(s.terms, term)
+		}
+	}
+	for _, term := range utils.AutocompleteTerms(swiftCode, bank.BankName) {
+		member := utils.AutocompleteMember(term, swiftCode, bank.IsHeadquarter)
+		if i, found := slices.BinarySearch(s.autocomplete, member); found {
+			s.autocomplete = slices.Delete(s.autocomplete, i, i+1)
+		}
+	}
+	delete(s.banks, swiftCode)
+	delete(s.updatedAt, swiftCode)
+}
+
+func (s *MemoryStore) FindBanksDetailsBySwiftCodes(ctx context.Context, swiftCodes []string) ([]types.BankDataDetails, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var banks []types.BankDataDetails
+	for _, swiftCode := range swiftCodes {
+		if bank, ok := s.banks[swiftCode]; ok {
+			banks = append(banks, bank)
+		}
+	}
+	return banks, nil
+}
+
+func (s *MemoryStore) findBanksDataMatching(ctx context.Context, matches func(swiftCode string) bool) ([]types.BankDataCore, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var banks []types.BankDataCore
+	for swiftCode, bank := range s.banks {
+		if matches(swiftCode) {
+			banks = append(banks, bank.BankDataCore)
+		}
+	}
+	sort.Slice(banks, func(i, j int) bool { return banks[i].SwiftCode < banks[j].SwiftCode })
+	return banks, nil
+}
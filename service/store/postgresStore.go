@@ -0,0 +1,519 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const postgresBankDataColumns = "swift_code, bank_name, address, country_iso2, country_name, is_headquarter"
+
+// postgresBankDetailsColumns are the columns read by scanVersionedBankDataDetails.
+const postgresBankDetailsColumns = postgresBankDataColumns + ", version"
+
+const postgresCreateBankData = `INSERT INTO bank_data (` + postgresBankDataColumns + `, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, now())
+		ON CONFLICT (swift_code) DO NOTHING`
+
+const postgresDeleteSearchTerms = "DELETE FROM bank_search_terms WHERE swift_code = $1"
+
+const postgresInsertSearchTerms = `INSERT INTO bank_search_terms (term, swift_code, weight)
+		SELECT term, $1, weight FROM unnest($2::text[], $3::int[]) AS t(term, weight)`
+
+const postgresDeleteAutocompleteTerms = "DELETE FROM bank_autocomplete_terms WHERE swift_code = $1"
+
+const postgresInsertAutocompleteTerms = `INSERT INTO bank_autocomplete_terms (term, swift_code)
+		SELECT term, $1 FROM unnest($2::text[]) AS t(term)`
+
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.pool.Ping(ctx)
+}
+
+func (s *PostgresStore) DoesSwiftCodeExist(ctx context.Context, swiftCode string) (int64, error) {
+	var count int64
+	err := s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM bank_data WHERE swift_code = $1", swiftCode).Scan(&count)
+	if err != nil {
+		return utils.SwiftCodeExistsError, err
+	}
+	return count, nil
+}
+
+func (s *PostgresStore) SaveBankData(ctx context.Context, data types.BankDataDetails) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `INSERT INTO bank_data (`+postgresBankDataColumns+`, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, now())
+			ON CONFLICT (swift_code) DO UPDATE SET
+				bank_name = EXCLUDED.bank_name,
+				address = EXCLUDED.address,
+				country_iso2 = EXCLUDED.country_iso2,
+				country_name = EXCLUDED.country_name,
+				is_headquarter = EXCLUDED.is_headquarter,
+				updated_at = EXCLUDED.updated_at,
+				version = bank_data.version + 1`,
+			data.SwiftCode, data.BankName, data.Address, data.CountryIso2, data.CountryName, data.IsHeadquarter,
+		)
+		if err != nil {
+			return err
+		}
+		return replaceBankTerms(ctx, tx, data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store data for key %s: %w", data.SwiftCode, err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) CreateBankData(ctx context.Context, data types.BankDataDetails) (bool, error) {
+	var created bool
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, postgresCreateBankData,
+			data.SwiftCode, data.BankName, data.Address, data.CountryIso2, data.CountryName, data.IsHeadquarter,
+		)
+		if err != nil || tag.RowsAffected() == 0 {
+			return err
+		}
+		created = true
+		return replaceBankTerms(ctx, tx, data)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create data for key %s: %w", data.SwiftCode, err)
+	}
+	return created, nil
+}
+
+// CreateBankDataBatch inserts all the data in a single transaction, which is rolled back
+// if any of the SWIFT codes is already taken. The returned SWIFT codes are the ones that already exist.
+func (s *PostgresStore) CreateBankDataBatch(ctx context.Context, data []types.BankDataDetails) ([]string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch of %d entries: %w", len(data), err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, entry := range data {
+		batch.Queue(postgresCreateBankData,
+			entry.SwiftCode, entry.BankName, entry.Address, entry.CountryIso2, entry.CountryName, entry.IsHeadquarter,
+		)
+	}
+	results := tx.SendBatch(ctx, batch)
+	var conflicts []string
+	for _, entry := range data {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return nil, fmt.Errorf("failed to create data for key %s: %w", entry.SwiftCode, err)
+		}
+		if tag.RowsAffected() == 0 {
+			conflicts = append(conflicts, entry.SwiftCode)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create batch of %d entries: %w", len(data), err)
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	batch = &pgx.Batch{}
+	for _, entry := range data {
+		queueReplaceBankTerms(batch, entry)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("failed to index batch of %d entries: %w", len(data), err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create batch of %d entries: %w", len(data), err)
+	}
+	return nil, nil
+}
+
+// UpdateBankData compares the version in the WHERE clause of the UPDATE, so the row lock makes a concurrent conditional update
+// see the increased version and fail.
+func (s *PostgresStore) UpdateBankData(ctx context.Context, data types.BankDataDetails, expectedVersion int64) (bool, error) {
+	var updated bool
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `UPDATE bank_data SET
+				bank_name = $2,
+				address = $3,
+				country_iso2 = $4,
+				country_name = $5,
+				is_headquarter = $6,
+				updated_at = now(),
+				version = version + 1
+			WHERE swift_code = $1 AND ($7::bigint = 0 OR version = $7)`,
+			data.SwiftCode, data.BankName, data.Address, data.CountryIso2, data.CountryName, data.IsHeadquarter, expectedVersion,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return checkVersion(data.SwiftCode, 0, expectedVersion)
+		}
+		updated = true
+		return replaceBankTerms(ctx, tx, data)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update data for key %s: %w", data.SwiftCode, err)
+	}
+	return updated, nil
+}
+
+func (s *PostgresStore) DeleteBankData(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	tag, err := s.pool.Exec(ctx, "DELETE FROM bank_data WHERE swift_code = $1 AND ($2::bigint = 0 OR version = $2)", swiftCode, expectedVersion)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = checkVersion(swiftCode, 0, expectedVersion)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete data for SWIFT code %s: %w", swiftCode, err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) FindBanksDataByCountryCode(ctx context.Context, countryCode string) ([]types.BankDataCore, error) {
+	banks, err := s.queryBanksData(ctx, "SELECT "+postgresBankDataColumns+" FROM bank_data WHERE country_code = $1 ORDER BY swift_code", countryCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch banks for country code %s: %w", countryCode, err)
+	}
+	return banks, nil
+}
+
+func (s *PostgresStore) FindBanksData(ctx context.Context, filter types.BankDataFilter) ([]types.BankDataCore, error) {
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.CountryIso2 != "" {
+		addCondition("country_code = $%d", filter.CountryIso2)
+	}
+	if filter.BankCode != "" {
+		addCondition("bank_code = $%d", filter.BankCode)
+	}
+	if filter.IsHeadquarter != nil {
+		addCondition("is_headquarter = $%d", *filter.IsHeadquarter)
+	}
+	if filter.BankName != "" {
+		addCondition("strpos(upper(bank_name), upper($%d)) > 0", filter.BankName)
+	}
+
+	query := "SELECT " + postgresBankDataColumns + " FROM bank_data"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	banks, err := s.queryBanksData(ctx, query+" ORDER BY swift_code", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch banks for filter: %w", err)
+	}
+	return banks, nil
+}
+
+func (s *PostgresStore) SearchBankData(ctx context.Context, terms []string, limit int) ([]types.SearchResult, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+postgresBankDataColumns+`, score FROM bank_data
+		JOIN (
+			SELECT swift_code, SUM(weight) AS score FROM bank_search_terms
+			WHERE term = ANY($1)
+			GROUP BY swift_code
+		) AS matches USING (swift_code)
+		ORDER BY score DESC, swift_code
+		LIMIT $2`,
+		terms, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search banks: %w", err)
+	}
+	defer rows.Close()
+
+	var results []types.SearchResult
+	for rows.Next() {
+		var bank types.BankDataDetails
+		var score int
+		err := rows.Scan(&bank.SwiftCode, &bank.BankName, &bank.Address, &bank.CountryIso2, &bank.CountryName, &bank.IsHeadquarter, &score)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search banks: %w", err)
+		}
+		results = append(results, types.SearchResult{BankDataCore: bank.BankDataCore, Score: score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search banks: %w", err)
+	}
+	return results, nil
+}
+
+func (s *PostgresStore) AutocompleteBankData(ctx context.Context, prefix string, limit int) ([]types.BankDataCore, error) {
+	banks, err := s.queryBanksData(ctx, `SELECT `+postgresBankDataColumns+` FROM bank_data
+		JOIN (
+			SELECT swift_code, MIN(term COLLATE "C") AS term FROM bank_autocomplete_terms
+			WHERE term LIKE $1
+			GROUP BY swift_code
+		) AS matches USING (swift_code)
+		ORDER BY is_headquarter DESC, term, swift_code COLLATE "C"
+		LIMIT $2`,
+		prefix+"%", limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to autocomplete prefix %s: %w", prefix, err)
+	}
+	return banks, nil
+}
+
+func (s *PostgresStore) FindCountriesStats(ctx context.Context) ([]types.CountryStats, error) {
+	rows, err := s.pool.Query(ctx, `SELECT country_code, COUNT(*), COUNT(*) FILTER (WHERE is_headquarter)
+		FROM bank_data
+		GROUP BY country_code
+		ORDER BY country_code`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count banks by country: %w", err)
+	}
+	defer rows.Close()
+
+	var countries []types.CountryStats
+	for rows.Next() {
+		var stats types.CountryStats
+		if err := rows.Scan(&stats.CountryIso2, &stats.Total, &stats.Headquarters); err != nil {
+			return nil, fmt.Errorf("failed to count banks by country: %w", err)
+		}
+		stats.CountryName = utils.GetCountryNameFromCountryCode(stats.CountryIso2)
+		stats.Branches = stats.Total - stats.Headquarters
+		countries = append(countries, stats)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count banks by country: %w", err)
+	}
+	return countries, nil
+}
+
+// ExportBankData reads the bank data in keyset pages of utils.ExportBatchSize rows ordered by SWIFT code, so no query
+// stays open while fn consumes the records. Rows stored before updated_at was added have no modification time.
+func (s *PostgresStore) ExportBankData(ctx context.Context, filter types.ExportFilter, fn func(types.BankDataRecord) error) error {
+	conditions := []string{"swift_code > $1"}
+	args := []any{""}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.CountryIso2 != "" {
+		addCondition("country_code = $%d", filter.CountryIso2)
+	}
+	if !filter.ModifiedSince.IsZero() {
+		addCondition("(updated_at IS NULL OR updated_at >= $%d)", filter.ModifiedSince)
+	}
+	query := "SELECT " + postgresBankDetailsColumns + ", updated_at FROM bank_data WHERE " + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(" ORDER BY swift_code LIMIT %d", utils.ExportBatchSize)
+
+	for {
+		records, err := s.queryBankRecords(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to export bank data: %w", err)
+		}
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if len(records) < utils.ExportBatchSize {
+			return nil
+		}
+		args[0] = records[len(records)-1].SwiftCode
+	}
+}
+
+func (s *PostgresStore) queryBankRecords(ctx context.Context, query string, args ...any) ([]types.BankDataRecord, error) {
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []types.BankDataRecord
+	for rows.Next() {
+		var record types.BankDataRecord
+		err := rows.Scan(&record.SwiftCode, &record.BankName, &record.Address, &record.CountryIso2, &record.CountryName,
+			&record.IsHeadquarter, &record.Version, &record.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (s *PostgresStore) SaveNationalBankCode(ctx context.Context, code types.NationalBankCode) error {
+	_, err := s.pool.Exec(ctx, `INSERT INTO national_bank_codes (country_iso2, national_bank_code, swift_code)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (country_iso2, national_bank_code) DO UPDATE SET swift_code = EXCLUDED.swift_code`,
+		code.CountryIso2, code.NationalBankCode, code.SwiftCode)
+	if err != nil {
+		return fmt.Errorf("failed to store national bank code %s of %s: %w", code.NationalBankCode, code.CountryIso2, err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) FindSwiftCodeByNationalBankCode(ctx context.Context, countryIso2 string, nationalBankCode string) (string, error) {
+	var swiftCode string
+	err := s.pool.QueryRow(ctx, "SELECT swift_code FROM national_bank_codes WHERE country_iso2 = $1 AND national_bank_code = $2",
+		countryIso2, nationalBankCode).Scan(&swiftCode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch national bank code %s of %s: %w", nationalBankCode, countryIso2, err)
+	}
+	return swiftCode, nil
+}
+
+// RebuildIndexes recomputes the search and autocomplete terms of every stored bank in a single transaction.
+// The other lookups are served by the table indexes, which Postgres keeps current on its own.
+func (s *PostgresStore) RebuildIndexes(ctx context.Context) (int, error) {
+	var count int
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, "SELECT "+postgresBankDataColumns+" FROM bank_data")
+		if err != nil {
+			return err
+		}
+		batch := &pgx.Batch{}
+		batch.Queue("DELETE FROM bank_search_terms")
+		batch.Queue("DELETE FROM bank_autocomplete_terms")
+		for rows.Next() {
+			bank, err := scanBankDataDetails(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			batch.Queue(postgresInsertSearchTerms, searchTermsArgs(*bank)...)
+			batch.Queue(postgresInsertAutocompleteTerms, bank.SwiftCode, utils.AutocompleteTerms(bank.SwiftCode, bank.BankName))
+			count++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to rebuild search indexes: %w", err)
+	}
+	return count, nil
+}
+
+func (s *PostgresStore) FindBranchesDataByHqSwiftCode(ctx context.Context, swiftCode string) ([]types.BankDataCore, error) {
+	banks, err := s.queryBanksData(ctx,
+		"SELECT "+postgresBankDataColumns+" FROM bank_data WHERE bic8 = $1 AND swift_code <> $2 ORDER BY swift_code",
+		swiftCode[:utils.SwiftCodeLength], swiftCode,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch branches for SWIFT code %s: %w", swiftCode, err)
+	}
+	return banks, nil
+}
+
+func (s *PostgresStore) FindBankDetailsBySwiftCode(ctx context.Context, swiftCode string) (*types.BankDataDetails, error) {
+	row := s.pool.QueryRow(ctx, "SELECT "+postgresBankDetailsColumns+" FROM bank_data WHERE swift_code = $1", swiftCode)
+	bank, err := scanVersionedBankDataDetails(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data from Postgres for key %s: %w", swiftCode, err)
+	}
+	return bank, nil
+}
+
+func (s *PostgresStore) FindBanksDetailsBySwiftCodes(ctx context.Context, swiftCodes []string) ([]types.BankDataDetails, error) {
+	rows, err := s.pool.Query(ctx, "SELECT "+postgresBankDetailsColumns+" FROM bank_data WHERE swift_code = ANY($1)", swiftCodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data for %d SWIFT codes: %w", len(swiftCodes), err)
+	}
+	defer rows.Close()
+
+	var banks []types.BankDataDetails
+	for rows.Next() {
+		bank, err := scanVersionedBankDataDetails(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch data for %d SWIFT codes: %w", len(swiftCodes), err)
+		}
+		banks = append(banks, *bank)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch data for %d SWIFT codes: %w", len(swiftCodes), err)
+	}
+	return banks, nil
+}
+
+func (s *PostgresStore) queryBanksData(ctx context.Context, query string, args ...any) ([]types.BankDataCore, error) {
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banks []types.BankDataCore
+	for rows.Next() {
+		bank, err := scanBankDataDetails(rows)
+		if err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank.BankDataCore)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return banks, nil
+}
+
+func replaceBankTerms(ctx context.Context, tx pgx.Tx, data types.BankDataDetails) error {
+	batch := &pgx.Batch{}
+	queueReplaceBankTerms(batch, data)
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// queueReplaceBankTerms replaces the search and autocomplete terms of the bank data.
+func queueReplaceBankTerms(batch *pgx.Batch, data types.BankDataDetails) {
+	batch.Queue(postgresDeleteSearchTerms, data.SwiftCode)
+	batch.Queue(postgresInsertSearchTerms, searchTermsArgs(data)...)
+	batch.Queue(postgresDeleteAutocompleteTerms, data.SwiftCode)
+	batch.Queue(postgresInsertAutocompleteTerms, data.SwiftCode, utils.AutocompleteTerms(data.SwiftCode, data.BankName))
+}
+
+func searchTermsArgs(data types.BankDataDetails) []any {
+	weights := utils.SearchTermWeights(data.BankName, data.Address)
+	terms := make([]string, 0, len(weights))
+	termWeights := make([]int32, 0, len(weights))
+	for term, weight := range weights {
+		terms = append(terms, term)
+		termWeights = append(termWeights, int32(weight))
+	}
+	return []any{data.SwiftCode, terms, termWeights}
+}
+
+func scanBankDataDetails(row pgx.Row) (*types.BankDataDetails, error) {
+	var bank types.BankDataDetails
+	err := row.Scan(&bank.SwiftCode, &bank.BankName, &bank.Address, &bank.CountryIso2, &bank.CountryName, &bank.IsHeadquarter)
+	if err != nil {
+		return nil, err
+	}
+	return &bank, nil
+}
+
+func scanVersionedBankDataDetails(row pgx.Row) (*types.BankDataDetails, error) {
+	var bank types.BankDataDetails
+	err := row.Scan(&bank.SwiftCode, &bank.BankName, &bank.Address, &bank.CountryIso2, &bank.CountryName, &bank.IsHeadquarter, &bank.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &bank, nil
+}
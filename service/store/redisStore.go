@@ -2,9 +2,13 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
@@ -12,61 +16,290 @@ import (
 )
 
 type RedisStore struct {
-	client redis.Client
+	client    redis.Client
+	batchSize int
 }
 
 func (s *RedisStore) Ping(ctx context.Context) error {
 	return s.client.Ping(ctx).Err()
 }
 
+// DoesSwiftCodeExist counts only hash keys, so other keys of a shared Redis are never reported as bank data.
 func (s *RedisStore) DoesSwiftCodeExist(ctx context.Context, swiftCode string) (int64, error) {
-	out, err := s.client.Exists(ctx, swiftCode).Result()
+	keyType, err := s.client.Type(ctx, swiftCode).Result()
 	if err != nil {
 		return utils.SwiftCodeExistsError, err
 	}
-	return out, nil
+	if keyType != utils.RedisTypeHash {
+		return 0, nil
+	}
+	return 1, nil
 }
 
 func (s *RedisStore) SaveBankData(ctx context.Context, data types.BankDataDetails) error {
-	hashData := map[string]interface{}{
-		utils.RedisHashAddress:       data.Address,
-		utils.RedisHashBankName:      data.BankName,
-		utils.RedisHashCountryISO2:   data.CountryIso2,
-		utils.RedisHashCountryName:   data.CountryName,
-		utils.RedisHashIsHeadquarter: data.IsHeadquarter,
-		utils.RedisHashSwiftCode:     data.SwiftCode,
-	}
-	if _, err := s.client.HSet(ctx, data.SwiftCode, hashData).Result(); err != nil {
+	_, err := s.saveBankDataIf(ctx, data, 0, func(exists bool) bool { return true })
+	if err != nil {
 		return fmt.Errorf("failed to store data for key %s: %w", data.SwiftCode, err)
 	}
 	return nil
 }
 
-func (s *RedisStore) DeleteBankData(ctx context.Context, swiftCode string) error {
-	_, err := s.client.Del(ctx, swiftCode).Result()
+// CreateBankData stores the data only if its SWIFT code is not taken yet. The key is WATCHed, so a concurrent
+// create aborts the transaction and the retry reports the code as already existing.
+func (s *RedisStore) CreateBankData(ctx context.Context, data types.BankDataDetails) (bool, error) {
+	created, err := s.saveBankDataIf(ctx, data, 0, func(exists bool) bool { return !exists })
 	if err != nil {
-		return fmt.Errorf("failed to delete data for SWIFT code %s: %w", swiftCode, err)
+		return false, fmt.Errorf("failed to create data for key %s: %w", data.SwiftCode, err)
+	}
+	return created, nil
+}
+
+// CreateBankDataBatch stores all the data in a single transaction, or nothing at all if any of the SWIFT codes
+// is already taken. The returned SWIFT codes are the ones that already exist.
+func (s *RedisStore) CreateBankDataBatch(ctx context.Context, data []types.BankDataDetails) ([]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(data))
+	for i, entry := range data {
+		keys[i] = entry.SwiftCode
+	}
+
+	var conflicts []string
+	createFn := func(tx *redis.Tx) error {
+		cmds, err := tx.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Exists(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		conflicts = nil
+		for i, cmd := range cmds {
+			if cmd.(*redis.IntCmd).Val() > 0 {
+				conflicts = append(conflicts, keys[i])
+			}
+		}
+		if len(conflicts) > 0 {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, entry := range data {
+				entry.Version = utils.BankDataInitialVersion
+				queueSaveBankData(ctx, pipe, entry)
+			}
+			return nil
+		})
+		return err
+	}
+
+	if err := s.watch(ctx, createFn, keys...); err != nil {
+		return nil, fmt.Errorf("failed to create batch of %d entries: %w", len(data), err)
+	}
+	return conflicts, nil
+}
+
+func (s *RedisStore) UpdateBankData(ctx context.Context, data types.BankDataDetails, expectedVersion int64) (bool, error) {
+	updated, err := s.saveBankDataIf(ctx, data, expectedVersion, func(exists bool) bool { return exists })
+	if err != nil {
+		return false, fmt.Errorf("failed to update data for key %s: %w", data.SwiftCode, err)
+	}
+	return updated, nil
+}
+
+// saveBankDataIf writes the data at the next version only when the stored version matches the expected one, if it is set,
+// and shouldSave accepts the existence of its key. The stored data is read under WATCH, so the version is compared
+// and the search and autocomplete terms of the replaced data are removed in the same transaction.
+func (s *RedisStore) saveBankDataIf(ctx context.Context, data types.BankDataDetails, expectedVersion int64, shouldSave func(exists bool) bool) (bool, error) {
+	saved := false
+	saveFn := func(tx *redis.Tx) error {
+		rows, err := tx.HGetAll(ctx, data.SwiftCode).Result()
+		if err != nil {
+			return err
+		}
+		var current types.BankDataDetails
+		if len(rows) > 0 {
+			current = *bankDetailsFromHash(rows)
+		}
+		if err := checkVersion(data.SwiftCode, current.Version, expectedVersion); err != nil || !shouldSave(len(rows) > 0) {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if len(rows) > 0 {
+				queueRemoveTermIndexes(ctx, pipe, &current)
+			}
+			next := data
+			next.Version = current.Version + 1
+			queueSaveBankData(ctx, pipe, next)
+			return nil
+		})
+		saved = err == nil
+		return err
+	}
+
+	if err := s.watch(ctx, saveFn, data.SwiftCode); err != nil {
+		return false, err
+	}
+	return saved, nil
+}
+
+// DeleteBankData compares the stored version under WATCH, so a conditional delete fails if the data is modified concurrently.
+func (s *RedisStore) DeleteBankData(ctx context.Context, swiftCode string, expectedVersion int64) error {
+	deleteFn := func(tx *redis.Tx) error {
+		rows, err := tx.HGetAll(ctx, swiftCode).Result()
+		if err != nil {
+			return err
+		}
+		var currentVersion int64
+		if len(rows) > 0 {
+			currentVersion = bankDetailsFromHash(rows).Version
+		}
+		if err := checkVersion(swiftCode, currentVersion, expectedVersion); err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, swiftCode)
+			for _, indexKey := range indexKeys(swiftCode) {
+				pipe.SRem(ctx, indexKey, swiftCode)
+			}
+			if len(rows) > 0 {
+				queueRemoveTermIndexes(ctx, pipe, bankDetailsFromHash(rows))
+			}
+			return nil
+		})
+		return err
 	}
 
+	if err := s.watch(ctx, deleteFn, swiftCode); err != nil {
+		return fmt.Errorf("failed to delete data for SWIFT code %s: %w", swiftCode, err)
+	}
 	return nil
 }
 
+// watch runs the transaction function with the keys WATCHed, retrying whenever a concurrent write aborts it.
+// The index rebuild lock is WATCHed as well, so no write is committed once a rebuild has started.
+func (s *RedisStore) watch(ctx context.Context, fn func(tx *redis.Tx) error, keys ...string) error {
+	guardedFn := func(tx *redis.Tx) error {
+		locked, err := tx.Exists(ctx, utils.RedisRebuildLock).Result()
+		if err != nil {
+			return err
+		}
+		if locked > 0 {
+			return utils.ErrIndexRebuildInProgress
+		}
+		return fn(tx)
+	}
+	keys = append(keys, utils.RedisRebuildLock)
+	for i := 0; i < utils.RedisTxMaxRetries; i++ {
+		err := s.client.Watch(ctx, guardedFn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return errors.New("too many concurrent modifications")
+}
+
 func (s *RedisStore) FindBanksDataByCountryCode(ctx context.Context, countryCode string) ([]types.BankDataCore, error) {
-	keys, err := s.client.Keys(ctx, utils.CountryCodeRegex(countryCode)).Result()
+	keys, err := s.client.SMembers(ctx, utils.CountryIndexKey(countryCode)).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch keys for country code %s: %w", countryCode, err)
 	}
 
-	return s.getBankDetailsByCodesConcurrently(ctx, keys, "")
+	return s.getBankDetailsByCodes(ctx, keys, "")
+}
+
+// FindBanksData narrows the candidates down with the country and bank code index sets,
+// so the filter has to set at least one of them.
+func (s *RedisStore) FindBanksData(ctx context.Context, filter types.BankDataFilter) ([]types.BankDataCore, error) {
+	var filterIndexKeys []string
+	if filter.CountryIso2 != "" {
+		filterIndexKeys = append(filterIndexKeys, utils.CountryIndexKey(filter.CountryIso2))
+	}
+	if filter.BankCode != "" {
+		filterIndexKeys = append(filterIndexKeys, utils.BankCodeIndexKey(filter.BankCode))
+	}
+	if len(filterIndexKeys) == 0 {
+		return nil, errors.New("filtering requires a country or a bank code")
+	}
+
+	keys, err := s.client.SInter(ctx, filterIndexKeys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys for filter: %w", err)
+	}
+	banks, err := s.getBankDetailsByCodes(ctx, keys, "")
+	return filterBanksData(banks, filter), err
+}
+
+// SearchBankData sums up the weights of the matched terms with ZUNION over the term sorted sets.
+func (s *RedisStore) SearchBankData(ctx context.Context, terms []string, limit int) ([]types.SearchResult, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(terms))
+	for i, term := range terms {
+		keys[i] = utils.SearchIndexKey(term)
+	}
+	matches, err := s.client.ZUnionWithScores(ctx, redis.ZStore{Keys: keys, Aggregate: "SUM"}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search terms: %w", err)
+	}
+
+	scores := make(map[string]int, len(matches))
+	for _, match := range matches {
+		scores[match.Member.(string)] = int(match.Score)
+	}
+	ranked := rankSearchScores(scores, limit)
+	swiftCodes := make([]string, len(ranked))
+	for i, score := range ranked {
+		swiftCodes[i] = score.swiftCode
+	}
+	banks, err := s.getBankDetailsByCodes(ctx, swiftCodes, "")
+	return searchResults(ranked, banks), err
+}
+
+// AutocompleteBankData reads the matching entries of both groups of the autocomplete sorted set with ZRANGEBYLEX.
+// Every bank has at most two entries, so twice the limit of entries per group always yields enough SWIFT codes.
+func (s *RedisStore) AutocompleteBankData(ctx context.Context, prefix string, limit int) ([]types.BankDataCore, error) {
+	pipe := s.client.Pipeline()
+	var cmds []*redis.StringSliceCmd
+	for _, group := range []string{utils.AutocompleteGroupHq, utils.AutocompleteGroupBranch} {
+		cmds = append(cmds, pipe.ZRangeByLex(ctx, utils.RedisIndexAutocomplete, &redis.ZRangeBy{
+			Min:   "[" + group + prefix,
+			Max:   "(" + group + prefix + autocompleteRangeEnd,
+			Count: int64(2 * limit),
+		}))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to autocomplete prefix %s: %w", prefix, err)
+	}
+
+	var members []string
+	for _, cmd := range cmds {
+		members = append(members, cmd.Val()...)
+	}
+	swiftCodes := autocompleteSwiftCodes(members, limit)
+	banks, err := s.getBankDetailsByCodes(ctx, swiftCodes, "")
+	return orderBanksBySwiftCodes(banks, swiftCodes), err
 }
 
 func (s *RedisStore) FindBranchesDataByHqSwiftCode(ctx context.Context, swiftCode string) ([]types.BankDataCore, error) {
-	branchKeys, err := s.client.Keys(ctx, utils.BranchRegex(swiftCode)).Result()
+	branchKeys, err := s.client.SMembers(ctx, utils.BranchIndexKey(swiftCode)).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch branches for SWIFT code %s: %w", swiftCode, err)
 	}
 
-	return s.getBankDetailsByCodesConcurrently(ctx, branchKeys, swiftCode)
+	return s.getBankDetailsByCodes(ctx, branchKeys, swiftCode)
+}
+
+// FindBanksDetailsBySwiftCodes fetches all the SWIFT codes with pipelined HGETALL calls. Missing SWIFT codes are skipped,
+// and the ones that could not be fetched are reported in a utils.BatchFetchError next to the fetched data.
+func (s *RedisStore) FindBanksDetailsBySwiftCodes(ctx context.Context, swiftCodes []string) ([]types.BankDataDetails, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.getBanksDataDetails(ctx, swiftCodes)
 }
 
 func (s *RedisStore) FindBankDetailsBySwiftCode(ctx context.Context, swiftCode string) (*types.BankDataDetails, error) {
@@ -78,7 +311,71 @@ func (s *RedisStore) FindBankDetailsBySwiftCode(ctx context.Context, swiftCode s
 		return nil, nil
 	}
 
-	bankDetails := &types.BankDataDetails{
+	return bankDetailsFromHash(rows), nil
+}
+
+// getBankDetailsByCodes fetches the given keys in pipelined chunks of batchSize, skipping currentSwiftCode.
+// Results are ordered by SWIFT code; keys that could not be fetched are reported in a utils.BatchFetchError.
+func (s *RedisStore) getBankDetailsByCodes(ctx context.Context, keys []string, currentSwiftCode string) ([]types.BankDataCore, error) {
+	sortedKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key != currentSwiftCode {
+			sortedKeys = append(sortedKeys, key)
+		}
+	}
+	sort.Strings(sortedKeys)
+
+	details, err := s.getBanksDataDetails(ctx, sortedKeys)
+	var banks []types.BankDataCore
+	for _, bank := range details {
+		banks = append(banks, bank.BankDataCore)
+	}
+	return banks, err
+}
+
+func (s *RedisStore) getBanksDataDetails(ctx context.Context, keys []string) (banks []types.BankDataDetails, err error) {
+	batchSize := s.batchSize
+	if batchSize <= 0 {
+		batchSize = len(keys)
+	}
+
+	failed := make(map[string]error)
+	for start := 0; start < len(keys); start += batchSize {
+		chunk := keys[start:min(start+batchSize, len(keys))]
+
+		pipe := s.client.Pipeline()
+		cmds := make([]*redis.MapStringStringCmd, len(chunk))
+		for i, key := range chunk {
+			cmds[i] = pipe.HGetAll(ctx, key)
+		}
+		pipe.Exec(ctx)
+
+		for i, cmd := range cmds {
+			rows, cmdErr := cmd.Result()
+			if cmdErr != nil {
+				failed[chunk[i]] = cmdErr
+				continue
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			banks = append(banks, *bankDetailsFromHash(rows))
+		}
+	}
+
+	if len(failed) > 0 {
+		err = utils.BatchFetchError{Errors: failed}
+	}
+	return
+}
+
+// bankDetailsFromHash reads the bank data, which is at the initial version if it was stored before versions were introduced.
+func bankDetailsFromHash(rows map[string]string) *types.BankDataDetails {
+	version, err := strconv.ParseInt(rows[utils.RedisHashVersion], 10, 64)
+	if err != nil {
+		version = utils.BankDataInitialVersion
+	}
+	return &types.BankDataDetails{
 		BankDataCore: types.BankDataCore{
 			Address:       rows[utils.RedisHashAddress],
 			BankName:      rows[utils.RedisHashBankName],
@@ -87,65 +384,314 @@ func (s *RedisStore) FindBankDetailsBySwiftCode(ctx context.Context, swiftCode s
 			SwiftCode:     rows[utils.RedisHashSwiftCode],
 		},
 		CountryName: rows[utils.RedisHashCountryName],
+		Version:     version,
+	}
+}
+
+// FindCountriesStats counts the members of every country index set, which are fetched in a single pipeline.
+func (s *RedisStore) FindCountriesStats(ctx context.Context) ([]types.CountryStats, error) {
+	swiftCodes, err := s.indexedSwiftCodes(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return countCountryStats(swiftCodes), nil
+}
 
-	return bankDetails, nil
+// countryIndexKeys lists the country index sets. Their members are the SWIFT codes of all the bank data, as every write
+// of the store maintains them, so other keys of a shared Redis are never taken for bank data.
+func (s *RedisStore) countryIndexKeys(ctx context.Context) ([]string, error) {
+	var countryKeys []string
+	iter := s.client.ScanType(ctx, 0, utils.RedisIndexCountry+"*", utils.RedisScanCount, utils.RedisTypeSet).Iterator()
+	for iter.Next(ctx) {
+		countryCode := strings.TrimPrefix(iter.Val(), utils.RedisIndexCountry)
+		if utils.GetCountryNameFromCountryCode(countryCode) != "" {
+			countryKeys = append(countryKeys, iter.Val())
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan country index keys: %w", err)
+	}
+	return countryKeys, nil
 }
 
-type bankDataChanResult struct {
-	bankData types.BankDataCore
-	err      error
+// indexedSwiftCodes returns the SWIFT codes of all the bank data, read from the country index sets.
+func (s *RedisStore) indexedSwiftCodes(ctx context.Context) ([]string, error) {
+	countryKeys, err := s.countryIndexKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cmds, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, countryKey := range countryKeys {
+			pipe.SMembers(ctx, countryKey)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch country index sets: %w", err)
+	}
+	var swiftCodes []string
+	for _, cmd := range cmds {
+		swiftCodes = append(swiftCodes, cmd.(*redis.StringSliceCmd).Val()...)
+	}
+	return swiftCodes, nil
 }
 
-func (s *RedisStore) getBankDetailsByCodesConcurrently(ctx context.Context, branchKeys []string, currentSwiftCode string) (branches []types.BankDataCore, aggregatedErr error) {
-	resultsChan := make(chan bankDataChanResult, len(branchKeys))
-	var (
-		wg   sync.WaitGroup
-		errs []string
-	)
+// ExportBankData walks the country index sets - or the one of the filter's country - with SSCAN cursors
+// and fetches the bank data in pipelined batches of utils.ExportBatchSize. The records are not ordered, and a failed batch aborts the export.
+func (s *RedisStore) ExportBankData(ctx context.Context, filter types.ExportFilter, fn func(types.BankDataRecord) error) error {
+	countryKeys := []string{utils.CountryIndexKey(filter.CountryIso2)}
+	if filter.CountryIso2 == "" {
+		var err error
+		if countryKeys, err = s.countryIndexKeys(ctx); err != nil {
+			return err
+		}
+	}
 
-	for _, branchKey := range branchKeys {
-		if branchKey == currentSwiftCode {
-			continue
+	// SSCAN may return a member more than once, so the exported SWIFT codes are remembered.
+	exported := make(map[string]struct{})
+	batch := make([]string, 0, utils.ExportBatchSize)
+	for _, countryKey := range countryKeys {
+		iter := s.client.SScan(ctx, countryKey, 0, "", utils.ExportBatchSize).Iterator()
+		for iter.Next(ctx) {
+			if _, ok := exported[iter.Val()]; ok {
+				continue
+			}
+			exported[iter.Val()] = struct{}{}
+			batch = append(batch, iter.Val())
+			if len(batch) < utils.ExportBatchSize {
+				continue
+			}
+			if err := s.exportBatch(ctx, batch, filter, fn); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("failed to scan bank data keys: %w", err)
 		}
-		wg.Add(1)
-		go s.fetchBankDetails(ctx, branchKey, resultsChan, &wg)
 	}
+	return s.exportBatch(ctx, batch, filter, fn)
+}
 
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
+func (s *RedisStore) exportBatch(ctx context.Context, swiftCodes []string, filter types.ExportFilter, fn func(types.BankDataRecord) error) error {
+	if len(swiftCodes) == 0 {
+		return nil
+	}
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(swiftCodes))
+	for i, swiftCode := range swiftCodes {
+		cmds[i] = pipe.HGetAll(ctx, swiftCode)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to fetch bank data: %w", err)
+	}
 
-	for result := range resultsChan {
-		if result.err != nil {
-			errs = append(errs, result.err.Error())
-		} else {
-			branches = append(branches, result.bankData)
+	for _, cmd := range cmds {
+		if len(cmd.Val()) == 0 {
+			continue
+		}
+		record := bankRecordFromHash(cmd.Val())
+		if !filter.Matches(record) {
+			continue
+		}
+		if err := fn(record); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+func bankRecordFromHash(rows map[string]string) types.BankDataRecord {
+	record := types.BankDataRecord{BankDataDetails: *bankDetailsFromHash(rows)}
+	if updatedAt, err := time.Parse(time.RFC3339Nano, rows[utils.RedisHashUpdatedAt]); err == nil {
+		record.UpdatedAt = &updatedAt
+	}
+	return record
+}
 
-	if len(errs) > 0 {
-		aggregatedErr = fmt.Errorf("encountered errors: %s", strings.Join(errs, "; "))
+// SaveNationalBankCode keeps the national bank codes of a country in a single hash, keyed by the national bank code.
+func (s *RedisStore) SaveNationalBankCode(ctx context.Context, code types.NationalBankCode) error {
+	err := s.client.HSet(ctx, utils.NationalBankCodesKey(code.CountryIso2), code.NationalBankCode, code.SwiftCode).Err()
+	if err != nil {
+		return fmt.Errorf("failed to store national bank code %s of %s: %w", code.NationalBankCode, code.CountryIso2, err)
 	}
-	return
+	return nil
+}
+
+func (s *RedisStore) FindSwiftCodeByNationalBankCode(ctx context.Context, countryIso2 string, nationalBankCode string) (string, error) {
+	swiftCode, err := s.client.HGet(ctx, utils.NationalBankCodesKey(countryIso2), nationalBankCode).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch national bank code %s of %s: %w", nationalBankCode, countryIso2, err)
+	}
+	return swiftCode, nil
 }
 
-func (s *RedisStore) fetchBankDetails(ctx context.Context, branchKey string, resultsChan chan<- bankDataChanResult, wg *sync.WaitGroup) {
-	defer wg.Done()
+// RebuildIndexes backfills the country, BIC8 and bank code index sets and the search and autocomplete sorted sets from the
+// bank data already stored in Redis. Writes are rejected while it holds the rebuild lock, so the indexes match the scanned data,
+// and every index is replaced in its own transaction, so lookups keep working while it runs. Index keys recorded in the
+// index registry and no longer needed are removed - other keys are never touched.
+func (s *RedisStore) RebuildIndexes(ctx context.Context) (int, error) {
+	token := strconv.FormatInt(time.Now().UnixNano(), 10)
+	locked, err := s.client.SetNX(ctx, utils.RedisRebuildLock, token, utils.RedisRebuildLockSeconds*time.Second).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock writes for the index rebuild: %w", err)
+	}
+	if !locked {
+		return 0, utils.ErrIndexRebuildInProgress
+	}
+
+	indexed, err := s.rebuildIndexes(ctx)
+	if unlockErr := s.unlockRebuild(context.WithoutCancel(ctx), token); err == nil {
+		err = unlockErr
+	}
+	return indexed, err
+}
 
-	branchFields, err := s.FindBankDetailsBySwiftCode(ctx, branchKey)
+func (s *RedisStore) rebuildIndexes(ctx context.Context) (int, error) {
+	swiftCodes, err := s.indexedSwiftCodes(ctx)
 	if err != nil {
-		resultsChan <- bankDataChanResult{err: fmt.Errorf("failed to fetch branch data for key %s: %w", branchKey, err)}
-	} else {
-		resultsChan <- bankDataChanResult{
-			bankData: types.BankDataCore{
-				Address:       branchFields.Address,
-				BankName:      branchFields.BankName,
-				CountryIso2:   branchFields.CountryIso2,
-				IsHeadquarter: branchFields.IsHeadquarter,
-				SwiftCode:     branchFields.SwiftCode,
-			},
+		return 0, err
+	}
+	banks, err := s.getBankDetailsByCodes(ctx, slices.Compact(slices.Sorted(slices.Values(swiftCodes))), "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read bank data: %w", err)
+	}
+
+	indexes := make(map[string][]interface{})
+	searchIndexes := make(map[string][]redis.Z)
+	for _, bank := range banks {
+		for _, indexKey := range indexKeys(bank.SwiftCode) {
+			indexes[indexKey] = append(indexes[indexKey], bank.SwiftCode)
+		}
+		for term, weight := range utils.SearchTermWeights(bank.BankName, bank.Address) {
+			indexKey := utils.SearchIndexKey(term)
+			searchIndexes[indexKey] = append(searchIndexes[indexKey], redis.Z{Score: float64(weight), Member: bank.SwiftCode})
+		}
+		for _, term := range utils.AutocompleteTerms(bank.SwiftCode, bank.BankName) {
+			member := utils.AutocompleteMember(term, bank.SwiftCode, bank.IsHeadquarter)
+			searchIndexes[utils.RedisIndexAutocomplete] = append(searchIndexes[utils.RedisIndexAutocomplete], redis.Z{Member: member})
+		}
+	}
+
+	rebuiltKeys := make([]interface{}, 0, len(indexes)+len(searchIndexes))
+	for indexKey, members := range indexes {
+		_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, indexKey)
+			pipe.SAdd(ctx, indexKey, members...)
+			pipe.SAdd(ctx, utils.RedisIndexKeys, indexKey)
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to rebuild index %s: %w", indexKey, err)
+		}
+		rebuiltKeys = append(rebuiltKeys, indexKey)
+	}
+	for indexKey, members := range searchIndexes {
+		_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, indexKey)
+			pipe.ZAdd(ctx, indexKey, members...)
+			pipe.SAdd(ctx, utils.RedisIndexKeys, indexKey)
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to rebuild index %s: %w", indexKey, err)
+		}
+		rebuiltKeys = append(rebuiltKeys, indexKey)
+	}
+
+	registered, err := s.client.SMembers(ctx, utils.RedisIndexKeys).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the index registry: %w", err)
+	}
+	for _, indexKey := range registered {
+		_, isIndex := indexes[indexKey]
+		_, isSearchIndex := searchIndexes[indexKey]
+		if isIndex || isSearchIndex {
+			continue
+		}
+		_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, indexKey)
+			pipe.SRem(ctx, utils.RedisIndexKeys, indexKey)
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to remove stale index %s: %w", indexKey, err)
+		}
+	}
+
+	return len(banks), nil
+}
+
+// unlockRebuild releases the rebuild lock, unless it expired in the meantime and the writes were let through.
+func (s *RedisStore) unlockRebuild(ctx context.Context, token string) error {
+	expired := false
+	err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, utils.RedisRebuildLock).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if current != token {
+			expired = true
+			return nil
 		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, utils.RedisRebuildLock)
+			return nil
+		})
+		return err
+	}, utils.RedisRebuildLock)
+	if err != nil {
+		return fmt.Errorf("failed to unlock writes after the index rebuild: %w", err)
+	}
+	if expired {
+		return fmt.Errorf("the index rebuild lock expired after %d seconds, run the rebuild again", utils.RedisRebuildLockSeconds)
+	}
+	return nil
+}
+
+func queueSaveBankData(ctx context.Context, pipe redis.Pipeliner, data types.BankDataDetails) {
+	hashData := map[string]interface{}{
+		utils.RedisHashAddress:       data.Address,
+		utils.RedisHashBankName:      data.BankName,
+		utils.RedisHashCountryISO2:   data.CountryIso2,
+		utils.RedisHashCountryName:   data.CountryName,
+		utils.RedisHashIsHeadquarter: data.IsHeadquarter,
+		utils.RedisHashSwiftCode:     data.SwiftCode,
+		utils.RedisHashUpdatedAt:     time.Now().UTC().Format(time.RFC3339Nano),
+		utils.RedisHashVersion:       data.Version,
+	}
+	pipe.HSet(ctx, data.SwiftCode, hashData)
+	registeredKeys := []interface{}{utils.RedisIndexAutocomplete}
+	for _, indexKey := range indexKeys(data.SwiftCode) {
+		pipe.SAdd(ctx, indexKey, data.SwiftCode)
+		registeredKeys = append(registeredKeys, indexKey)
+	}
+	for term, weight := range utils.SearchTermWeights(data.BankName, data.Address) {
+		pipe.ZAdd(ctx, utils.SearchIndexKey(term), redis.Z{Score: float64(weight), Member: data.SwiftCode})
+		registeredKeys = append(registeredKeys, utils.SearchIndexKey(term))
+	}
+	for _, term := range utils.AutocompleteTerms(data.SwiftCode, data.BankName) {
+		pipe.ZAdd(ctx, utils.RedisIndexAutocomplete, redis.Z{Member: utils.AutocompleteMember(term, data.SwiftCode, data.IsHeadquarter)})
+	}
+	pipe.SAdd(ctx, utils.RedisIndexKeys, registeredKeys...)
+}
+
+func queueRemoveTermIndexes(ctx context.Context, pipe redis.Pipeliner, data *types.BankDataDetails) {
+	for term := range utils.SearchTermWeights(data.BankName, data.Address) {
+		pipe.ZRem(ctx, utils.SearchIndexKey(term), data.SwiftCode)
+	}
+	for _, term := range utils.AutocompleteTerms(data.SwiftCode, data.BankName) {
+		pipe.ZRem(ctx, utils.RedisIndexAutocomplete, utils.AutocompleteMember(term, data.SwiftCode, data.IsHeadquarter))
+	}
+}
+
+func indexKeys(swiftCode string) []string {
+	return []string{
+		utils.CountryIndexKey(swiftCode[utils.CountryCodeOffset : utils.CountryCodeOffset+utils.CountryCodeLength]),
+		utils.BranchIndexKey(swiftCode),
+		utils.BankCodeIndexKey(swiftCode),
 	}
 }
@@ -3,12 +3,14 @@ package store_test
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/DroppedHard/SWIFT-service/config"
 	"github.com/DroppedHard/SWIFT-service/db"
 	"github.com/DroppedHard/SWIFT-service/service/store"
+	"github.com/DroppedHard/SWIFT-service/types"
 	"github.com/DroppedHard/SWIFT-service/utils"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/suite"
@@ -31,16 +33,29 @@ func (suite *RedisStoreTestSuite) prepareData() {
 			fmt.Printf("Failed to populate key %s: %v\n", entry.Key, err)
 			return
 		}
+		countryKey := utils.CountryIndexKey(entry.Key[utils.CountryCodeOffset : utils.CountryCodeOffset+utils.CountryCodeLength])
+		if _, err := suite.client.SAdd(ctx, countryKey, entry.Key).Result(); err != nil {
+			fmt.Printf("Failed to index key %s: %v\n", entry.Key, err)
+			return
+		}
 		fmt.Printf("Successfully populated key: %s\n", entry.Key)
 	}
+	if _, err := suite.store.RebuildIndexes(ctx); err != nil {
+		fmt.Printf("Failed to rebuild indexes: %v\n", err)
+	}
 }
 func (suite *RedisStoreTestSuite) deleteData() {
 	ctx := context.Background()
 	for _, entry := range TestRedisData {
+		countryKey := utils.CountryIndexKey(entry.Key[utils.CountryCodeOffset : utils.CountryCodeOffset+utils.CountryCodeLength])
 		if _, err := suite.client.Del(ctx, entry.Key).Result(); err != nil {
 			fmt.Printf("Failed to delete key %s: %v\n", entry.Key, err)
 			return
 		}
+		if _, err := suite.client.SRem(ctx, countryKey, entry.Key).Result(); err != nil {
+			fmt.Printf("Failed to unindex key %s: %v\n", entry.Key, err)
+			return
+		}
 		fmt.Printf("Successfully deleted key: %s\n", entry.Key)
 	}
 }
@@ -101,6 +116,15 @@ func (suite *RedisStoreTestSuite) TestDoesSwiftCodeExist() {
 			suite.Equal(count, int64(0))
 		})
 	}
+	suite.Run("Negative case - key that does not hold bank data", func() {
+		foreignKey := NonexistentSwiftCodes[0]
+		suite.NoError(suite.client.Set(ctx, foreignKey, "not a hash", 0).Err())
+		defer suite.client.Del(ctx, foreignKey)
+
+		count, err := suite.store.DoesSwiftCodeExist(ctx, foreignKey)
+		suite.NoError(err)
+		suite.Equal(int64(0), count)
+	})
 	suite.Run("Negative Cases - Invalid Contexts", func() {
 		swiftCode := TestRedisData[0].Key
 		suite.Run("Canceled Context", func() {
@@ -139,7 +163,8 @@ func (suite *RedisStoreTestSuite) TestSaveBankDataAndFindBankDetailsBySwiftCode(
 
 				data, err := suite.store.FindBankDetailsBySwiftCode(ctx, entry.SwiftCode)
 				suite.NoError(err)
-				suite.Equal(&entry, data)
+				suite.Require().NotNil(data)
+				suite.Equal(withoutVersions(entry), withoutVersions(*data))
 			})
 		}
 	})
@@ -177,7 +202,7 @@ func (suite *RedisStoreTestSuite) TestDeleteBankData() {
 
 		swiftCodes := []string{NewBankData[0].SwiftCode, NewBankData[1].SwiftCode}
 		for _, code := range swiftCodes {
-			err := suite.store.DeleteBankData(ctx, code)
+			err := suite.store.DeleteBankData(ctx, code, 0)
 			suite.NoError(err)
 		}
 
@@ -194,7 +219,7 @@ func (suite *RedisStoreTestSuite) TestDeleteBankData() {
 			canceledCtx, cancel := context.WithCancel(context.Background())
 			cancel()
 
-			err := suite.store.DeleteBankData(canceledCtx, exampleData.SwiftCode)
+			err := suite.store.DeleteBankData(canceledCtx, exampleData.SwiftCode, 0)
 			suite.Error(err)
 			suite.Contains(err.Error(), "context canceled")
 		})
@@ -203,7 +228,7 @@ func (suite *RedisStoreTestSuite) TestDeleteBankData() {
 			expiredCtx, cancel := context.WithTimeout(context.Background(), 0)
 			defer cancel()
 
-			err := suite.store.DeleteBankData(expiredCtx, exampleData.SwiftCode)
+			err := suite.store.DeleteBankData(expiredCtx, exampleData.SwiftCode, 0)
 			suite.Error(err)
 			suite.Contains(err.Error(), "context deadline exceeded")
 		})
@@ -345,3 +370,112 @@ func (suite *RedisStoreTestSuite) TestFindBankDetailsBySwiftCode() {
 		suite.Nil(banksData)
 	})
 }
+
+func (suite *RedisStoreTestSuite) TestRebuildIndexes() {
+	ctx := context.Background()
+	staleSwiftCode := NonexistentSwiftCodes[0]
+	hqSwiftCode := TestRedisData[0].Key
+
+	suite.Run("Positive Case", func() {
+		suite.NoError(suite.client.SAdd(ctx, utils.BranchIndexKey(staleSwiftCode), staleSwiftCode).Err())
+		suite.NoError(suite.client.SAdd(ctx, utils.RedisIndexKeys, utils.BranchIndexKey(staleSwiftCode)).Err())
+		suite.NoError(suite.client.SRem(ctx, utils.BranchIndexKey(hqSwiftCode), TestRedisData[1].Key).Err())
+
+		indexed, err := suite.store.RebuildIndexes(ctx)
+		suite.NoError(err)
+		suite.GreaterOrEqual(indexed, len(TestRedisData))
+
+		isStale, err := suite.client.SIsMember(ctx, utils.BranchIndexKey(staleSwiftCode), staleSwiftCode).Result()
+		suite.NoError(err)
+		suite.False(isStale)
+
+		branches, err := suite.store.FindBranchesDataByHqSwiftCode(ctx, hqSwiftCode)
+		suite.NoError(err)
+		suite.Len(branches, suite.countBranches(hqSwiftCode))
+	})
+
+	suite.Run("Keys of other applications are kept", func() {
+		foreignKey := utils.RedisIndexCountry + "other-app"
+		suite.NoError(suite.client.SAdd(ctx, foreignKey, "member").Err())
+		defer suite.client.Del(ctx, foreignKey)
+
+		_, err := suite.store.RebuildIndexes(ctx)
+		suite.NoError(err)
+
+		exists, err := suite.client.Exists(ctx, foreignKey).Result()
+		suite.NoError(err)
+		suite.Equal(int64(1), exists)
+	})
+
+	suite.Run("Keys outside the country indexes are not bank data", func() {
+		foreignKey := NonexistentSwiftCodes[1]
+		suite.NoError(suite.client.HSet(ctx, foreignKey, utils.RedisHashSwiftCode, foreignKey).Err())
+		defer suite.client.Del(ctx, foreignKey)
+
+		_, err := suite.store.RebuildIndexes(ctx)
+		suite.NoError(err)
+
+		indexed, err := suite.client.SIsMember(ctx, utils.CountryIndexKey(foreignKey[utils.CountryCodeOffset:utils.CountryCodeOffset+utils.CountryCodeLength]), foreignKey).Result()
+		suite.NoError(err)
+		suite.False(indexed)
+
+		err = suite.store.ExportBankData(ctx, types.ExportFilter{}, func(record types.BankDataRecord) error {
+			suite.NotEqual(foreignKey, record.SwiftCode)
+			return nil
+		})
+		suite.NoError(err)
+	})
+
+	suite.Run("Writes are rejected during the rebuild", func() {
+		bank, err := suite.store.FindBankDetailsBySwiftCode(ctx, hqSwiftCode)
+		suite.NoError(err)
+		suite.NoError(suite.client.Set(ctx, utils.RedisRebuildLock, "other rebuild", 0).Err())
+		defer suite.client.Del(ctx, utils.RedisRebuildLock)
+
+		_, err = suite.store.UpdateBankData(ctx, *bank, 0)
+		suite.ErrorIs(err, utils.ErrIndexRebuildInProgress)
+		suite.ErrorIs(suite.store.DeleteBankData(ctx, bank.SwiftCode, 0), utils.ErrIndexRebuildInProgress)
+		_, err = suite.store.RebuildIndexes(ctx)
+		suite.ErrorIs(err, utils.ErrIndexRebuildInProgress)
+	})
+
+	suite.Run("Cancelled context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := suite.store.RebuildIndexes(ctx)
+		suite.Error(err)
+	})
+}
+
+func (suite *RedisStoreTestSuite) TestPipelinedBatchFetch() {
+	ctx := context.Background()
+	countryCode := "PL"
+	batchedStore := store.NewStoreWithBatchSize(&suite.client, 2)
+
+	suite.Run("Deterministic order across batches", func() {
+		banksData, err := batchedStore.FindBanksDataByCountryCode(ctx, countryCode)
+		suite.NoError(err)
+		suite.Len(banksData, suite.countCountryMatching(countryCode))
+		suite.True(sort.SliceIsSorted(banksData, func(i, j int) bool {
+			return banksData[i].SwiftCode < banksData[j].SwiftCode
+		}))
+	})
+
+	suite.Run("Failed keys are reported", func() {
+		brokenKey := NonexistentSwiftCodes[1]
+		suite.NoError(suite.client.Set(ctx, brokenKey, "not a hash", 0).Err())
+		suite.NoError(suite.client.SAdd(ctx, utils.CountryIndexKey(countryCode), brokenKey).Err())
+		defer func() {
+			suite.client.Del(ctx, brokenKey)
+			suite.client.SRem(ctx, utils.CountryIndexKey(countryCode), brokenKey)
+		}()
+
+		banksData, err := batchedStore.FindBanksDataByCountryCode(ctx, countryCode)
+		suite.Len(banksData, suite.countCountryMatching(countryCode))
+
+		var batchErr utils.BatchFetchError
+		suite.ErrorAs(err, &batchErr)
+		suite.Equal([]string{brokenKey}, batchErr.FailedKeys())
+	})
+}
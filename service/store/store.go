@@ -1,7 +1,162 @@
 package store
 
-import "github.com/redis/go-redis/v9"
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DroppedHard/SWIFT-service/config"
+	"github.com/DroppedHard/SWIFT-service/types"
+	"github.com/DroppedHard/SWIFT-service/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"go.etcd.io/bbolt"
+)
+
+func filterBanksData(banks []types.BankDataCore, filter types.BankDataFilter) []types.BankDataCore {
+	var filtered []types.BankDataCore
+	for _, bank := range banks {
+		if filter.Matches(bank) {
+			filtered = append(filtered, bank)
+		}
+	}
+	return filtered
+}
+
+type searchScore struct {
+	swiftCode string
+	score     int
+}
+
+// rankSearchScores orders the matched SWIFT codes by descending score, with the SWIFT code breaking ties,
+// and keeps the first limit of them.
+func rankSearchScores(scores map[string]int, limit int) []searchScore {
+	ranked := make([]searchScore, 0, len(scores))
+	for swiftCode, score := range scores {
+		ranked = append(ranked, searchScore{swiftCode: swiftCode, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].swiftCode < ranked[j].swiftCode
+	})
+	return ranked[:min(limit, len(ranked))]
+}
+
+func searchResults(ranked []searchScore, banks []types.BankDataCore) []types.SearchResult {
+	banksBySwiftCode := make(map[string]types.BankDataCore, len(banks))
+	for _, bank := range banks {
+		banksBySwiftCode[bank.SwiftCode] = bank
+	}
+	var results []types.SearchResult
+	for _, score := range ranked {
+		if bank, ok := banksBySwiftCode[score.swiftCode]; ok {
+			results = append(results, types.SearchResult{BankDataCore: bank, Score: score.score})
+		}
+	}
+	return results
+}
+
+// autocompleteRangeEnd is greater than any byte of UTF-8 text, so prefix + autocompleteRangeEnd bounds all entries starting with prefix.
+const autocompleteRangeEnd = "\xff"
+
+// autocompleteSwiftCodes returns the SWIFT codes of the sorted autocomplete entries, in order of their first entry.
+func autocompleteSwiftCodes(members []string, limit int) []string {
+	seen := make(map[string]bool)
+	var swiftCodes []string
+	for _, member := range members {
+		swiftCode := member[strings.LastIndex(member, utils.AutocompleteSeparator)+1:]
+		if seen[swiftCode] {
+			continue
+		}
+		if len(swiftCodes) == limit {
+			break
+		}
+		seen[swiftCode] = true
+		swiftCodes = append(swiftCodes, swiftCode)
+	}
+	return swiftCodes
+}
+
+func orderBanksBySwiftCodes(banks []types.BankDataCore, swiftCodes []string) []types.BankDataCore {
+	banksBySwiftCode := make(map[string]types.BankDataCore, len(banks))
+	for _, bank := range banks {
+		banksBySwiftCode[bank.SwiftCode] = bank
+	}
+	var ordered []types.BankDataCore
+	for _, swiftCode := range swiftCodes {
+		if bank, ok := banksBySwiftCode[swiftCode]; ok {
+			ordered = append(ordered, bank)
+		}
+	}
+	return ordered
+}
+
+// countCountryStats groups the SWIFT codes by the country they belong to, ordered by country code.
+// Headquarters are told apart by the XXX branch code, the same way stored bank data is validated.
+func countCountryStats(swiftCodes []string) []types.CountryStats {
+	statsByCountry := make(map[string]*types.CountryStats)
+	for _, swiftCode := range swiftCodes {
+		countryCode := swiftCode[utils.CountryCodeOffset : utils.CountryCodeOffset+utils.CountryCodeLength]
+		stats, ok := statsByCountry[countryCode]
+		if !ok {
+			stats = &types.CountryStats{CountryIso2: countryCode, CountryName: utils.GetCountryNameFromCountryCode(countryCode)}
+			statsByCountry[countryCode] = stats
+		}
+		stats.Total++
+		if strings.HasSuffix(swiftCode, utils.BranchSuffix) {
+			stats.Headquarters++
+		} else {
+			stats.Branches++
+		}
+	}
+	countries := make([]types.CountryStats, 0, len(statsByCountry))
+	for _, stats := range statsByCountry {
+		countries = append(countries, *stats)
+	}
+	sort.Slice(countries, func(i, j int) bool { return countries[i].CountryIso2 < countries[j].CountryIso2 })
+	return countries
+}
+
+// checkVersion rejects a conditional write - one with a non-zero expected version - of bank data stored at another version.
+// Missing bank data has the version 0, so it is rejected as well.
+func checkVersion(swiftCode string, currentVersion int64, expectedVersion int64) error {
+	if expectedVersion != 0 && currentVersion != expectedVersion {
+		return utils.VersionMismatchError{SwiftCode: swiftCode, ExpectedVersion: expectedVersion}
+	}
+	return nil
+}
 
 func NewStore(client *redis.Client) *RedisStore {
-	return &RedisStore{client: *client}
+	return NewStoreWithBatchSize(client, config.Envs.DBBatchSize)
+}
+
+func NewStoreWithBatchSize(client *redis.Client, batchSize int) *RedisStore {
+	return &RedisStore{client: *client, batchSize: batchSize}
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		banks:             make(map[string]types.BankDataDetails),
+		updatedAt:         make(map[string]time.Time),
+		terms:             make(map[string]map[string]int),
+		nationalBankCodes: make(map[string]string),
+	}
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+func NewBoltStore(db *bbolt.DB) *BoltStore {
+	return &BoltStore{db: db}
+}
+
+func NewRedisIdempotencyStore(client *redis.Client, ttl time.Duration) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client, ttl: ttl}
+}
+
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{ttl: ttl, records: make(map[string]memoryIdempotencyRecord)}
 }
@@ -1,41 +1,284 @@
 package types
 
-import "context"
+import (
+	"context"
+	"encoding/xml"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DroppedHard/SWIFT-service/utils"
+)
 
 type BankDataCore struct {
-	Address       string `json:"address" validate:"required"`
-	BankName      string `json:"bankName" validate:"required"`
-	CountryIso2   string `json:"countryISO2" validate:"required,countryISO2"`
-	IsHeadquarter bool   `json:"isHeadquarter" validate:"boolRequired"`
-	SwiftCode     string `json:"swiftCode" validate:"required,len=11,swiftCode"`
+	Address       string `json:"address" xml:"address" validate:"required"`
+	BankName      string `json:"bankName" xml:"bankName" validate:"required"`
+	CountryIso2   string `json:"countryISO2" xml:"countryISO2" validate:"required,countryISO2"`
+	IsHeadquarter bool   `json:"isHeadquarter" xml:"isHeadquarter" validate:"boolRequired"`
+	SwiftCode     string `json:"swiftCode" xml:"swiftCode" validate:"required,len=11,swiftCode"`
 }
 
+// BankDataDetails is the stored bank data. Version is set by the store - it starts at 1 and is increased by every write,
+// so it is ignored in request bodies.
 type BankDataDetails struct {
 	BankDataCore
-	CountryName string `json:"countryName" validate:"required"`
+	CountryName string `json:"countryName" xml:"countryName" validate:"required"`
+	Version     int64  `json:"version,omitempty" xml:"version,omitempty"`
 }
 
 type BankHeadquatersResponse struct {
+	XMLName xml.Name `json:"-" xml:"bank"`
 	BankDataDetails
-	Branches []BankDataCore `json:"branches"`
+	Branches []BankDataCore `json:"branches" xml:"branches>branch"`
+}
+
+type BankBranchResponse struct {
+	XMLName xml.Name `json:"-" xml:"bank"`
+	BankDataDetails
+	Headquarters *BankDataCore `json:"headquarters,omitempty" xml:"headquarters,omitempty"`
+	SiblingCount int           `json:"siblingCount" xml:"siblingCount"`
+	Orphan       bool          `json:"orphan" xml:"orphan"`
 }
 
 type CountrySwiftCodesResponse struct {
-	CountryIso2 string         `json:"countryISO2"`
-	CountryName string         `json:"countryName"`
-	SwiftCodes  []BankDataCore `json:"swiftCodes"`
+	XMLName     xml.Name       `json:"-" xml:"country"`
+	CountryIso2 string         `json:"countryISO2" xml:"countryISO2"`
+	CountryName string         `json:"countryName" xml:"countryName"`
+	SwiftCodes  []BankDataCore `json:"swiftCodes" xml:"swiftCodes>bank"`
+	Total       int            `json:"total" xml:"total"`
+	NextCursor  string         `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+}
+
+type BatchItemResult struct {
+	Index     int    `json:"index"`
+	SwiftCode string `json:"swiftCode"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	Code      string `json:"code,omitempty"`
+}
+
+type BatchCreateResponse struct {
+	Atomic  bool              `json:"atomic"`
+	Created int               `json:"created"`
+	Results []BatchItemResult `json:"results"`
+}
+
+type SwiftCodesResponse struct {
+	XMLName    xml.Name       `json:"-" xml:"banks"`
+	SwiftCodes []BankDataCore `json:"swiftCodes" xml:"swiftCodes>bank"`
+	Total      int            `json:"total" xml:"total"`
+	NextCursor string         `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+}
+
+// BankDataFilter narrows bank data down to the entries matching all of the set fields. The country is the one of the SWIFT code,
+// like in the country indexes of the stores, whatever CountryIso2 was stored with the bank data.
+type BankDataFilter struct {
+	CountryIso2   string
+	BankCode      string
+	IsHeadquarter *bool
+	BankName      string
+}
+
+func (f BankDataFilter) Matches(bank BankDataCore) bool {
+	if f.CountryIso2 != "" && !utils.MatchesCountryCode(bank.SwiftCode, f.CountryIso2) {
+		return false
+	}
+	if f.BankCode != "" && !strings.HasPrefix(bank.SwiftCode, f.BankCode) {
+		return false
+	}
+	if f.IsHeadquarter != nil && bank.IsHeadquarter != *f.IsHeadquarter {
+		return false
+	}
+	return f.BankName == "" || strings.Contains(strings.ToUpper(bank.BankName), strings.ToUpper(f.BankName))
+}
+
+// BankDataRecord is the bank data together with the time it was last stored. Bank data stored before
+// the modification times were tracked has no UpdatedAt.
+type BankDataRecord struct {
+	BankDataDetails
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ExportFilter narrows the exported bank data down to the country of the SWIFT code and to the records modified at or after
+// ModifiedSince. Records without a modification time are always exported.
+type ExportFilter struct {
+	CountryIso2   string
+	ModifiedSince time.Time
+}
+
+func (f ExportFilter) Matches(record BankDataRecord) bool {
+	if f.CountryIso2 != "" && !utils.MatchesCountryCode(record.SwiftCode, f.CountryIso2) {
+		return false
+	}
+	return f.ModifiedSince.IsZero() || record.UpdatedAt == nil || !record.UpdatedAt.Before(f.ModifiedSince)
+}
+
+type ExportQuery struct {
+	Filter ExportFilter
+	Format string
+}
+
+// VersionCondition is the If-Match header of a write - the bank data versions carried by its strong entity tags.
+// A write without the header, or with "*", is unconditional.
+type VersionCondition struct {
+	Conditional bool
+	Versions    []int64
+}
+
+func (c VersionCondition) Matches(version int64) bool {
+	return !c.Conditional || slices.Contains(c.Versions, version)
+}
+
+type SearchQuery struct {
+	Query string
+	Terms []string
+	Limit int
+}
+
+type SearchResult struct {
+	BankDataCore
+	Score int `json:"score"`
+}
+
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+type AutocompleteQuery struct {
+	Prefix string
+	Limit  int
+}
+
+type AutocompleteResponse struct {
+	Prefix  string         `json:"prefix"`
+	Results []BankDataCore `json:"results"`
+}
+
+type SwiftCodeStructure struct {
+	SwiftCode     string `json:"swiftCode"`
+	Valid         bool   `json:"valid"`
+	Error         string `json:"error,omitempty"`
+	Format        string `json:"format,omitempty"`
+	Normalized    string `json:"normalized,omitempty"`
+	BankCode      string `json:"bankCode,omitempty"`
+	CountryIso2   string `json:"countryISO2,omitempty"`
+	CountryName   string `json:"countryName,omitempty"`
+	LocationCode  string `json:"locationCode,omitempty"`
+	BranchCode    string `json:"branchCode,omitempty"`
+	IsHeadquarter bool   `json:"isHeadquarter"`
+	IsTestCode    bool   `json:"isTestCode"`
+	IsPassive     bool   `json:"isPassive"`
+}
+
+type LookupRequest struct {
+	SwiftCodes []string `json:"swiftCodes"`
+}
+
+type LookupResponse struct {
+	Found    []BankDataDetails `json:"found"`
+	NotFound []string          `json:"notFound"`
+	Invalid  []string          `json:"invalid"`
+	Failed   []string          `json:"failed,omitempty"`
+}
+
+type BankGroupResponse struct {
+	BankCode  string             `json:"bankCode"`
+	Total     int                `json:"total"`
+	Countries []BankGroupCountry `json:"countries"`
+}
+
+type BankGroupCountry struct {
+	CountryIso2  string         `json:"countryISO2"`
+	CountryName  string         `json:"countryName"`
+	Headquarters []BankDataCore `json:"headquarters"`
+	Branches     []BankDataCore `json:"branches"`
+}
+
+type CountryStats struct {
+	CountryIso2  string `json:"countryISO2"`
+	CountryName  string `json:"countryName"`
+	Total        int    `json:"total"`
+	Headquarters int    `json:"headquarters"`
+	Branches     int    `json:"branches"`
+}
+
+type CountriesResponse struct {
+	Countries []CountryStats `json:"countries"`
+}
+
+// NationalBankCode maps the bank identifier used in IBANs of the country to the SWIFT code of the bank.
+type NationalBankCode struct {
+	CountryIso2      string `json:"countryISO2"`
+	NationalBankCode string `json:"nationalBankCode"`
+	SwiftCode        string `json:"swiftCode"`
+}
+
+type IbanBicResponse struct {
+	Iban             string           `json:"iban"`
+	CountryIso2      string           `json:"countryISO2"`
+	NationalBankCode string           `json:"nationalBankCode"`
+	SwiftCode        string           `json:"swiftCode"`
+	Bank             BankDataDetails  `json:"bank"`
+	Headquarter      *BankDataDetails `json:"headquarter,omitempty"`
 }
 
 type BankDataStore interface {
 	DoesSwiftCodeExist(ctx context.Context, swiftCode string) (int64, error)
 	SaveBankData(ctx context.Context, data BankDataDetails) error
-	DeleteBankData(ctx context.Context, swiftCode string) error
+	CreateBankData(ctx context.Context, data BankDataDetails) (bool, error)
+	CreateBankDataBatch(ctx context.Context, data []BankDataDetails) ([]string, error)
+	UpdateBankData(ctx context.Context, data BankDataDetails, expectedVersion int64) (bool, error)
+	DeleteBankData(ctx context.Context, swiftCode string, expectedVersion int64) error
 	FindBanksDataByCountryCode(ctx context.Context, countryCode string) ([]BankDataCore, error)
+	FindBanksData(ctx context.Context, filter BankDataFilter) ([]BankDataCore, error)
 	FindBranchesDataByHqSwiftCode(ctx context.Context, swiftCode string) ([]BankDataCore, error)
 	FindBankDetailsBySwiftCode(ctx context.Context, swiftCode string) (*BankDataDetails, error)
+	FindBanksDetailsBySwiftCodes(ctx context.Context, swiftCodes []string) ([]BankDataDetails, error)
+	SearchBankData(ctx context.Context, terms []string, limit int) ([]SearchResult, error)
+	AutocompleteBankData(ctx context.Context, prefix string, limit int) ([]BankDataCore, error)
+	FindCountriesStats(ctx context.Context) ([]CountryStats, error)
+	ExportBankData(ctx context.Context, filter ExportFilter, fn func(BankDataRecord) error) error
+	SaveNationalBankCode(ctx context.Context, code NationalBankCode) error
+	FindSwiftCodeByNationalBankCode(ctx context.Context, countryIso2 string, nationalBankCode string) (string, error)
 	Ping(ctx context.Context) error
 }
 
+// IdempotencyRecord is the response of a write request stored under its Idempotency-Key, together with the hash of
+// the request. A record without a status belongs to a request which is still being handled.
+type IdempotencyRecord struct {
+	RequestHash string              `json:"requestHash"`
+	Status      int                 `json:"status,omitempty"`
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body,omitempty"`
+}
+
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey stores the record under a free key and returns nil, or returns the record already stored under it.
+	ReserveIdempotencyKey(ctx context.Context, key string, record IdempotencyRecord) (*IdempotencyRecord, error)
+	SaveIdempotencyRecord(ctx context.Context, key string, record IdempotencyRecord) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+}
+
 type ReturnMessage struct {
 	Message string `json:"message"`
 }
+
+type Problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail"`
+	Code   string       `json:"code"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// IndexRebuilder is implemented by stores whose lookup indexes can be rebuilt from the stored bank data.
+type IndexRebuilder interface {
+	RebuildIndexes(ctx context.Context) (int, error)
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrIndexRebuildInProgress rejects writes to the bank data while the Redis indexes are rebuilt, so none of them is lost or left stale.
+var ErrIndexRebuildInProgress = errors.New("the indexes are being rebuilt, try again later")
+
+type BatchFetchError struct {
+	Errors map[string]error
+}
+
+func (e BatchFetchError) FailedKeys() []string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (e BatchFetchError) Error() string {
+	return fmt.Sprintf("failed to fetch data for keys: %s", strings.Join(e.FailedKeys(), ", "))
+}
+
+// VersionMismatchError reports that a conditional write was rejected, because the bank data is no longer
+// at the expected version - it was modified or deleted in the meantime.
+type VersionMismatchError struct {
+	SwiftCode       string
+	ExpectedVersion int64
+}
+
+func (e VersionMismatchError) Error() string {
+	return fmt.Sprintf("the bank data of %s is no longer at version %d", e.SwiftCode, e.ExpectedVersion)
+}
@@ -1,6 +1,32 @@
 package utils
 
 const (
-	SwiftCodeExistsError = -1
-	SwiftCodeLength      = 8
+	SwiftCodeExistsError        = -1
+	SwiftCodeLength             = 8
+	SwiftCodeFullLength         = 11
+	BankCodeLength              = 4
+	BankNameMaxLength           = 200
+	CountryCodeOffset           = 4
+	CountryCodeLength           = 2
+	RedisScanCount              = 1000
+	RedisTxMaxRetries           = 10
+	PatchMaxAttempts            = 5
+	MigrationBatchTimeoutSecs   = 30
+	BatchMaxItems               = 1000
+	LookupMaxSwiftCodes         = 1000
+	PageDefaultLimit            = 100
+	PageMaxLimit                = 1000
+	SearchMinTokenLength        = 2
+	SearchMaxQueryLength        = 200
+	SearchDefaultLimit          = 20
+	SearchMaxLimit              = 100
+	SearchWeightBankName        = 2
+	SearchWeightAddress         = 1
+	AutocompleteMaxPrefixLength = 100
+	AutocompleteDefaultLimit    = 10
+	AutocompleteMaxLimit        = 50
+	ExportBatchSize             = 500
+	IdempotencyKeyMaxLength     = 255
+	BankDataInitialVersion      = 1
+	RedisRebuildLockSeconds     = 3600
 )
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// searchLetterReplacer folds letters that have no Unicode decomposition into their base letters.
+var searchLetterReplacer = strings.NewReplacer(
+	"Ł", "L", "ł", "l",
+	"Ø", "O", "ø", "o",
+	"Đ", "D", "đ", "d",
+	"Ħ", "H", "ħ", "h",
+	"Æ", "AE", "æ", "ae",
+	"Œ", "OE", "œ", "oe",
+	"ß", "SS", "ı", "i",
+)
+
+// SearchTokens splits the text into unique, upper-cased and accent-free words, in order of appearance.
+func SearchTokens(text string) []string {
+	words := searchWords(text)
+	seen := make(map[string]bool, len(words))
+	var tokens []string
+	for _, word := range words {
+		if len(word) < SearchMinTokenLength || seen[word] {
+			continue
+		}
+		seen[word] = true
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// SearchTermWeights returns the search index terms of a bank together with their weights.
+// A term found in both the bank name and the address gets both weights.
+func SearchTermWeights(bankName string, address string) map[string]int {
+	weights := make(map[string]int)
+	for _, token := range SearchTokens(bankName) {
+		weights[token] += SearchWeightBankName
+	}
+	for _, token := range SearchTokens(address) {
+		weights[token] += SearchWeightAddress
+	}
+	return weights
+}
+
+// FoldSearchText upper-cases the text, strips its accents and punctuation, and separates the remaining words with single spaces.
+func FoldSearchText(text string) string {
+	return strings.Join(searchWords(text), " ")
+}
+
+// AutocompleteTerms returns the prefix index terms of a bank - its SWIFT code and folded bank name.
+func AutocompleteTerms(swiftCode string, bankName string) []string {
+	terms := []string{swiftCode}
+	if name := FoldSearchText(bankName); name != "" && name != swiftCode {
+		terms = append(terms, name)
+	}
+	return terms
+}
+
+// AutocompleteMember builds the prefix index entry of a term. Headquarters entries sort before branch entries,
+// and the SWIFT code after the NUL separator keeps entries of banks sharing a name unique.
+func AutocompleteMember(term string, swiftCode string, isHeadquarter bool) string {
+	return AutocompleteGroup(isHeadquarter) + term + AutocompleteSeparator + swiftCode
+}
+
+func AutocompleteGroup(isHeadquarter bool) string {
+	if isHeadquarter {
+		return AutocompleteGroupHq
+	}
+	return AutocompleteGroupBranch
+}
+
+func searchWords(text string) []string {
+	folding := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folding, searchLetterReplacer.Replace(text))
+	if err != nil {
+		folded = text
+	}
+	return strings.FieldsFunc(strings.ToUpper(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func SearchIndexKey(term string) string {
+	return RedisIndexSearchTerm + term
+}
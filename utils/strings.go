@@ -1,20 +1,115 @@
 package utils
 
 const (
-	PathParamSwiftCode     = "swift-code"
-	PathParamCountryIso2   = "countryISO2"
-	ValidatorSwiftCode     = "swiftCode"
-	ValidatorBoolRequired  = "boolRequired"
-	ValidatorCountryIso2   = "countryISO2"
-	BranchSuffix           = "XXX"
-	ApiPrefix              = "/v1"
-	RedisStoreTrue         = "1"
-	RedisStoreFalse        = "0"
-	RedisHashSwiftCode     = "swiftCode"
-	RedisHashAddress       = "address"
-	RedisHashIsHeadquarter = "isHeadquarter"
-	RedisHashCountryISO2   = "countryISO2"
-	RedisHashBankName      = "bankName"
-	RedisHashCountryName   = "countryName"
-	ResponseMessageField   = "message"
+	PathParamSwiftCode      = "swift-code"
+	PathParamCountryIso2    = "countryISO2"
+	PathParamIban           = "iban"
+	PathParamBankCode       = "bankCode"
+	QueryParamAtomic        = "atomic"
+	QueryParamSearch        = "q"
+	QueryParamPrefix        = "prefix"
+	QueryParamCountry       = "country"
+	QueryParamIsHq          = "isHeadquarter"
+	QueryParamBankName      = "bankName"
+	QueryParamBankCode      = "bankCode"
+	QueryParamLimit         = "limit"
+	QueryParamCursor        = "cursor"
+	QueryParamSortBy        = "sortBy"
+	QueryParamFormat        = "format"
+	QueryParamModifiedSince = "modifiedSince"
+	ExportFormatNdjson      = "ndjson"
+	ExportFormatCsv         = "csv"
+	SortBySwiftCode         = "swiftCode"
+	SortByBankName          = "bankName"
+	ValidatorSwiftCode      = "swiftCode"
+	ValidatorBoolRequired   = "boolRequired"
+	ValidatorCountryIso2    = "countryISO2"
+	BranchSuffix            = "XXX"
+	SwiftFormatBic8         = "BIC8"
+	SwiftFormatBic11        = "BIC11"
+	SwiftLocationTest       = '0'
+	SwiftLocationPassive    = '1'
+	HeaderContentLocation   = "Content-Location"
+	HeaderContentType       = "Content-Type"
+	ContentTypeJson         = "application/json"
+	ContentTypeProblemJson  = "application/problem+json"
+	ContentTypeXml          = "application/xml"
+	ContentTypeNdjson       = "application/x-ndjson"
+	ContentTypeCsv          = "text/csv"
+	HeaderAccept            = "Accept"
+	HeaderVary              = "Vary"
+	HeaderETag              = "ETag"
+	HeaderIfNoneMatch       = "If-None-Match"
+	HeaderIfMatch           = "If-Match"
+	ETagVersionSeparator    = "-"
+	HeaderIdempotencyKey    = "Idempotency-Key"
+	HeaderIdempotentReplay  = "Idempotent-Replayed"
+	CsvSeparator            = ';'
+	ProblemTypePrefix       = "urn:swift-service:problem:"
+	ProblemInvalidSwiftCode = "invalid_swift_code"
+	ProblemInvalidCountry   = "invalid_country_code"
+	ProblemInvalidBankCode  = "invalid_bank_code"
+	ProblemInvalidIban      = "invalid_iban"
+	ProblemInvalidPathParam = "invalid_path_parameter"
+	ProblemInvalidQuery     = "invalid_query_parameter"
+	ProblemInvalidBody      = "invalid_body"
+	ProblemValidationFailed = "validation_failed"
+	ProblemCountryMismatch  = "country_mismatch"
+	ProblemHqMismatch       = "headquarter_mismatch"
+	ProblemSwiftCodeChanged = "swift_code_mismatch"
+	ProblemDuplicate        = "duplicate"
+	ProblemNotFound         = "not_found"
+	ProblemNotAcceptable    = "not_acceptable"
+	ProblemStoreUnavailable = "store_unavailable"
+	ProblemInternal         = "internal_error"
+	ProblemInvalidIdemKey   = "invalid_idempotency_key"
+	ProblemIdemKeyReused    = "idempotency_key_reused"
+	ProblemIdemKeyInUse     = "idempotency_key_in_progress"
+	ProblemInvalidIfMatch   = "invalid_if_match"
+	ProblemPrecondition     = "precondition_failed"
+	ProblemEditConflict     = "edit_conflict"
+	ApiPrefix               = "/v1"
+	RedisStoreTrue          = "1"
+	RedisStoreFalse         = "0"
+	RedisHashSwiftCode      = "swiftCode"
+	RedisHashAddress        = "address"
+	RedisHashIsHeadquarter  = "isHeadquarter"
+	RedisHashCountryISO2    = "countryISO2"
+	RedisHashBankName       = "bankName"
+	RedisHashCountryName    = "countryName"
+	RedisHashUpdatedAt      = "updatedAt"
+	RedisHashVersion        = "version"
+	RedisIndexCountry       = "idx:country:"
+	RedisIndexBranch        = "idx:bic8:"
+	RedisIndexBankCode      = "idx:bank:"
+	RedisIndexSearchTerm    = "idx:term:"
+	RedisIndexAutocomplete  = "idx:autocomplete"
+	RedisIndexKeys          = "idx:keys"
+	RedisRebuildLock        = "lock:index-rebuild"
+	RedisNationalBankCodes  = "nbc:"
+	RedisIdempotencyKeys    = "idem:"
+	RedisTypeHash           = "hash"
+	RedisTypeSet            = "set"
+	ResponseMessageField    = "message"
+	BatchStatusCreated      = "created"
+	BatchStatusConflict     = "conflict"
+	BatchStatusInvalid      = "invalid"
+	BatchStatusSkipped      = "skipped"
+	BatchStatusError        = "error"
+	StoreBackendRedis       = "redis"
+	StoreBackendMemory      = "memory"
+	StoreBackendPostgres    = "postgres"
+	StoreBackendBolt        = "bolt"
+	BoltBucketBankData      = "bank_data"
+	BoltBucketCountryIndex  = "country_index"
+	BoltBucketSearchIndex   = "search_index"
+	BoltBucketAutocomplete  = "autocomplete_index"
+	BoltBucketBankCodes     = "national_bank_codes"
+	AutocompleteGroupHq     = "0"
+	AutocompleteGroupBranch = "1"
+	AutocompleteSeparator   = "\x00"
 )
+
+var CsvBankDataHeader = []string{"SWIFT CODE", "NAME", "ADDRESS"}
+
+var BoltBuckets = []string{BoltBucketBankData, BoltBucketCountryIndex, BoltBucketSearchIndex, BoltBucketAutocomplete, BoltBucketBankCodes}
@@ -18,6 +18,34 @@ func CountryCodeRegex(countryCode string) string {
 	return "????" + countryCode + "?????"
 }
 
+func CountryIndexKey(countryCode string) string {
+	return RedisIndexCountry + countryCode
+}
+
+func BranchIndexKey(swiftCode string) string {
+	return RedisIndexBranch + swiftCode[:SwiftCodeLength]
+}
+
+func BankCodeIndexKey(swiftCode string) string {
+	return RedisIndexBankCode + swiftCode[:BankCodeLength]
+}
+
+func NationalBankCodesKey(countryCode string) string {
+	return RedisNationalBankCodes + countryCode
+}
+
+func IdempotencyKey(key string) string {
+	return RedisIdempotencyKeys + key
+}
+
+func MatchesBranch(swiftCode string, hqSwiftCode string) bool {
+	return len(swiftCode) == SwiftCodeFullLength && swiftCode[:SwiftCodeLength] == hqSwiftCode[:SwiftCodeLength]
+}
+
+func MatchesCountryCode(swiftCode string, countryCode string) bool {
+	return len(swiftCode) == SwiftCodeFullLength && swiftCode[CountryCodeOffset:CountryCodeOffset+CountryCodeLength] == countryCode
+}
+
 func GetCountryNameFromCountryCode(countryCode string) string {
 	result, ok := country.ByAlpha2Code(country.Alpha2Code(countryCode))
 	if ok {
@@ -34,6 +62,40 @@ func GetCountryCodeFromSwiftCode(swiftCode string) (string, error) {
 	return strings.ToUpper(parsed.CountryCode()), nil
 }
 
+// CleanSwiftCode trims whitespace around the SWIFT code and uppercases it.
+func CleanSwiftCode(swiftCode string) string {
+	return strings.ToUpper(strings.TrimSpace(swiftCode))
+}
+
+// NormalizeSwiftCode returns the canonical form of the SWIFT code under which bank data is stored -
+// cleaned up and, for a BIC8, expanded into the equivalent BIC11 of the primary office.
+func NormalizeSwiftCode(swiftCode string) string {
+	swiftCode = CleanSwiftCode(swiftCode)
+	if len(swiftCode) == SwiftCodeLength {
+		return swiftCode + BranchSuffix
+	}
+	return swiftCode
+}
+
+// HeadquarterSwiftCode returns the BIC11 of the primary office sharing the BIC8 of the given SWIFT code.
+func HeadquarterSwiftCode(swiftCode string) string {
+	return NormalizeSwiftCode(swiftCode[:SwiftCodeLength])
+}
+
+// CleanIban removes the spaces of the printed IBAN format and uppercases it.
+func CleanIban(value string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
+
+// NationalBankCodeCandidates lists the national bank codes an IBAN can be mapped by, most specific first -
+// the bank code followed by the branch code, if the country has one, and the bank code alone.
+func NationalBankCodeCandidates(bankCode string, branchCode string) []string {
+	if branchCode == "" {
+		return []string{bankCode}
+	}
+	return []string{bankCode + branchCode, bankCode}
+}
+
 func Xor(a bool, b bool) bool {
 	return (a || b) && !(a && b)
 }
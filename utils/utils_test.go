@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/DroppedHard/SWIFT-service/utils"
@@ -17,6 +18,21 @@ func TestCountryCodeRegex(t *testing.T) {
 	assert.Equal(t, "????PL?????", result)
 }
 
+func TestCountryIndexKey(t *testing.T) {
+	result := utils.CountryIndexKey("PL")
+	assert.Equal(t, "idx:country:PL", result)
+}
+
+func TestBranchIndexKey(t *testing.T) {
+	result := utils.BranchIndexKey("ALBPPLPWXXX")
+	assert.Equal(t, "idx:bic8:ALBPPLPW", result)
+}
+
+func TestBankCodeIndexKey(t *testing.T) {
+	assert.Equal(t, "idx:bank:ALBP", utils.BankCodeIndexKey("ALBPPLPWXXX"))
+	assert.Equal(t, "idx:bank:ALBP", utils.BankCodeIndexKey("ALBP"))
+}
+
 func TestGetCountryNameFromCountryCode(t *testing.T) {
 	result := utils.GetCountryNameFromCountryCode("PL")
 	assert.Equal(t, "POLAND", result)
@@ -35,6 +51,31 @@ func TestGetCountryCodeFromSwiftCode(t *testing.T) {
 	assert.Empty(t, result)
 }
 
+func TestNormalizeSwiftCode(t *testing.T) {
+	assert.Equal(t, "ALBPPLPWXXX", utils.NormalizeSwiftCode("ALBPPLPW"))
+	assert.Equal(t, "ALBPPLPWCUS", utils.NormalizeSwiftCode("ALBPPLPWCUS"))
+	assert.Equal(t, "ALBPPLPWXXX", utils.NormalizeSwiftCode(" albpplpw\t"))
+	assert.Equal(t, "ALBPPLPWCUS", utils.CleanSwiftCode(" albpplpwcus "))
+}
+
+func TestHeadquarterSwiftCode(t *testing.T) {
+	assert.Equal(t, "ALBPPLPWXXX", utils.HeadquarterSwiftCode("ALBPPLPWCUS"))
+	assert.Equal(t, "ALBPPLPWXXX", utils.HeadquarterSwiftCode("ALBPPLPWXXX"))
+}
+
+func TestNationalBankCodesKey(t *testing.T) {
+	assert.Equal(t, "nbc:PL", utils.NationalBankCodesKey("PL"))
+}
+
+func TestCleanIban(t *testing.T) {
+	assert.Equal(t, "PL37249000500000000000000000", utils.CleanIban(" pl37 2490 0050 0000 0000 0000 0000 "))
+}
+
+func TestNationalBankCodeCandidates(t *testing.T) {
+	assert.Equal(t, []string{"2490005", "249"}, utils.NationalBankCodeCandidates("249", "0005"))
+	assert.Equal(t, []string{"37040044"}, utils.NationalBankCodeCandidates("37040044", ""))
+}
+
 func TestXor(t *testing.T) {
 	assert.True(t, utils.Xor(true, false))
 	assert.False(t, utils.Xor(false, false))
@@ -51,3 +92,43 @@ func TestGetFunctionName(t *testing.T) {
 	result = utils.GetFunctionName(invalidFn)
 	assert.Equal(t, "", result)
 }
+
+func TestBatchFetchError(t *testing.T) {
+	err := utils.BatchFetchError{Errors: map[string]error{
+		"ALBPPLPWXXX": fmt.Errorf("first"),
+		"AAISALTRXXX": fmt.Errorf("second"),
+	}}
+	assert.Equal(t, []string{"AAISALTRXXX", "ALBPPLPWXXX"}, err.FailedKeys())
+	assert.Equal(t, "failed to fetch data for keys: AAISALTRXXX, ALBPPLPWXXX", err.Error())
+}
+
+func TestSearchTokens(t *testing.T) {
+	assert.Equal(t, []string{"LODZ", "DRITAN", "HOXHA"}, utils.SearchTokens("Łódź, Dritan-Hoxha łódź"))
+	assert.Equal(t, []string{"STRASSE", "12", "MUNCHEN"}, utils.SearchTokens("Straße 12 a, München"))
+	assert.Empty(t, utils.SearchTokens(" - a / ! "))
+}
+
+func TestSearchTermWeights(t *testing.T) {
+	result := utils.SearchTermWeights("Bank Warszawa", "Prosta 18 Warszawa")
+	assert.Equal(t, map[string]int{"BANK": 2, "WARSZAWA": 3, "PROSTA": 1, "18": 1}, result)
+}
+
+func TestSearchIndexKey(t *testing.T) {
+	assert.Equal(t, "idx:term:WARSZAWA", utils.SearchIndexKey("WARSZAWA"))
+}
+
+func TestFoldSearchText(t *testing.T) {
+	assert.Equal(t, "BANK POLSKA KASA OPIEKI S A", utils.FoldSearchText("  Bank Polska Kasa Opieki S.A."))
+	assert.Equal(t, "LODZ", utils.FoldSearchText("Łódź"))
+	assert.Empty(t, utils.FoldSearchText(" - "))
+}
+
+func TestAutocompleteTerms(t *testing.T) {
+	assert.Equal(t, []string{"ALBPPLPWXXX", "ALIOR BANK S A"}, utils.AutocompleteTerms("ALBPPLPWXXX", "Alior Bank S.A."))
+	assert.Equal(t, []string{"ALBPPLPWXXX"}, utils.AutocompleteTerms("ALBPPLPWXXX", ""))
+}
+
+func TestAutocompleteMember(t *testing.T) {
+	assert.Equal(t, "0ALIOR BANK\x00ALBPPLPWXXX", utils.AutocompleteMember("ALIOR BANK", "ALBPPLPWXXX", true))
+	assert.Equal(t, "1ALIOR BANK\x00ALBPPLPW123", utils.AutocompleteMember("ALIOR BANK", "ALBPPLPW123", false))
+}
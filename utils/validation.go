@@ -2,6 +2,9 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/jbub/banking/swift"
@@ -15,10 +18,30 @@ type ValidationError struct {
 }
 
 func (v ValidationError) Error() string {
-	return fmt.Sprintf("validation failed: %v", v.Errors)
+	fields := make([]string, 0, len(v.Errors))
+	for field, message := range v.Errors {
+		fields = append(fields, field+": "+message)
+	}
+	sort.Strings(fields)
+	return "validation failed: " + strings.Join(fields, "; ")
+}
+
+// ProblemError tags the error with the problem code reported to the client.
+type ProblemError struct {
+	Code string
+	Err  error
+}
+
+func (p ProblemError) Error() string {
+	return p.Err.Error()
+}
+
+func (p ProblemError) Unwrap() error {
+	return p.Err
 }
 
 func init() {
+	Validate.RegisterTagNameFunc(jsonFieldName)
 	errSwift := Validate.RegisterValidation(ValidatorSwiftCode, swiftCodeValidation)
 	errIso2 := Validate.RegisterValidation(ValidatorCountryIso2, countryIso2Validation)
 	errBool := Validate.RegisterValidation(ValidatorBoolRequired, boolValidation)
@@ -33,6 +56,15 @@ func init() {
 	}
 }
 
+// jsonFieldName makes validation errors refer to the fields by their JSON names.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
 func swiftCodeValidation(fl validator.FieldLevel) bool {
 	swiftCode := fl.Field().String()
 	if err := swift.Validate(swiftCode); err != nil {